@@ -13,7 +13,6 @@ import (
 
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/test"
-	"github.com/nyaruka/goflow/utils"
 )
 
 var docSets = []struct {
@@ -51,12 +50,6 @@ func buildDocs(baseDir string) (string, error) {
 	}
 	defer server.Close()
 
-	utils.SetRand(utils.NewSeededRand(123456))
-	defer utils.SetRand(utils.DefaultRand)
-
-	utils.SetUUIDGenerator(utils.NewSeededUUID4Generator(123456))
-	defer utils.SetUUIDGenerator(utils.DefaultUUIDGenerator)
-
 	session, err := test.CreateTestSession(testServerPort, nil)
 	if err != nil {
 		return "", fmt.Errorf("error creating example session: %s", err)
@@ -31,6 +31,37 @@ type FlowTest struct {
 	Outputs      []json.RawMessage        `json:"outputs"`
 }
 
+// reads the caller events sent to each call in this test, and the events which were recorded as output from each call
+func (t *FlowTest) readEvents() ([][]flows.Event, [][]flows.Event, error) {
+	var err error
+	callerEvents := make([][]flows.Event, len(t.CallerEvents))
+	for i := range t.CallerEvents {
+		if callerEvents[i], err = events.ReadEvents(t.CallerEvents[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	recorded := make([][]flows.Event, len(t.Outputs))
+	for i := range t.Outputs {
+		output := &Output{}
+		if err := json.Unmarshal(t.Outputs[i], output); err != nil {
+			return nil, nil, err
+		}
+
+		recorded[i] = make([]flows.Event, len(output.Events))
+		for e := range output.Events {
+			envelope := &utils.TypedEnvelope{}
+			if err := json.Unmarshal(output.Events[e], envelope); err != nil {
+				return nil, nil, err
+			}
+			if recorded[i][e], err = events.EventFromEnvelope(envelope); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return callerEvents, recorded, nil
+}
+
 func marshalEventLog(eventLog []flows.Event) []json.RawMessage {
 	envelopes, err := events.EventsToEnvelopes(eventLog)
 	marshaled := make([]json.RawMessage, len(envelopes))
@@ -115,19 +146,19 @@ func main() {
 	testdata := filepath.Join(os.Getenv("GOPATH"), "src/github.com/nyaruka/goflow/cmd/flowrunner/testdata")
 
 	writePtr := flag.Bool("write", false, "Whether to write a _test.json file for this flow")
+	replayPtr := flag.Bool("replay", false, "Whether to replay a recorded _test.json file instead of running interactively")
 	contactFile := flag.String("contact", filepath.Join(testdata, "contacts/default.json"), "The location of the JSON file defining the contact to use, defaulting to test/contacts/default.json")
 
 	flag.Parse()
 
 	if len(flag.Args()) != 2 {
-		fmt.Printf("\nUsage: runner [-write] <assets.json> flow_uuid\n\n")
+		fmt.Printf("\nUsage: runner [-write] <assets.json> flow_uuid\n       runner -replay <assets.json> <test.json>\n\n")
 		os.Exit(1)
 	}
 
 	httpClient := utils.NewHTTPClient("goflow-flowrunner")
 
 	assetsFilename := flag.Args()[0]
-	startFlowUUID := flows.FlowUUID(flag.Args()[1])
 
 	fmt.Printf("Parsing: %s\n", assetsFilename)
 	assetsJSON, err := ioutil.ReadFile(assetsFilename)
@@ -139,6 +170,13 @@ func main() {
 		log.Fatal("Error reading assets: ", err)
 	}
 
+	if *replayPtr {
+		replay(assetCache, httpClient, flag.Args()[1])
+		return
+	}
+
+	startFlowUUID := flows.FlowUUID(flag.Args()[1])
+
 	// create our environment
 	la, _ := time.LoadLocation("America/Los_Angeles")
 	env := utils.NewEnvironment(utils.DateFormatYearMonthDay, utils.TimeFormatHourMinute, la, utils.LanguageList{}, utils.RedactionPolicyNone)
@@ -255,3 +293,52 @@ func main() {
 		}
 	}
 }
+
+// replays the session recorded in the given test file and reports any differences in the events generated by each call
+func replay(assetCache *assets.AssetCache, httpClient *utils.HTTPClient, testFilename string) {
+	testJSON, err := ioutil.ReadFile(testFilename)
+	if err != nil {
+		log.Fatal("Error reading test file: ", err)
+	}
+	flowTest := &FlowTest{}
+	if err := json.Unmarshal(testJSON, flowTest); err != nil {
+		log.Fatal("Error unmarshalling test file: ", err)
+	}
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), httpClient)
+	trigger, err := triggers.ReadTrigger(session, flowTest.Trigger)
+	if err != nil {
+		log.Fatal("Error reading trigger: ", err)
+	}
+	callerEvents, recorded, err := flowTest.readEvents()
+	if err != nil {
+		log.Fatal("Error reading events: ", err)
+	}
+
+	session, calls, err := engine.Replay(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), httpClient, trigger, callerEvents, recorded)
+	if err != nil {
+		log.Fatal("Error replaying session: ", err)
+	}
+
+	for c, call := range calls {
+		if call.Matches() {
+			fmt.Printf("call %d: %d events match\n", c, len(call.Events))
+			continue
+		}
+		for _, diff := range call.Diffs {
+			fmt.Printf("call %d, event %d:\n  recorded: %s\n  replayed: %s\n", c, diff.Index, describeEvent(diff.Recorded), describeEvent(diff.Replayed))
+		}
+	}
+
+	fmt.Printf("session ended with status %s\n", session.Status())
+}
+
+// describes the given event for replay output
+func describeEvent(event flows.Event) string {
+	if event == nil {
+		return "nothing"
+	}
+	envelope, _ := utils.EnvelopeFromTyped(event)
+	eventJSON, _ := utils.JSONMarshal(envelope)
+	return string(eventJSON)
+}
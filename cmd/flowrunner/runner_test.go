@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/assets"
@@ -43,6 +44,8 @@ var flowTests = []struct {
 	{"no_contact.json", "no_contact_test.json"},
 	{"redact_urns.json", "redact_urns_test.json"},
 	{"router_tests.json", "router_tests_test.json"},
+	{"resthook.json", "resthook_test.json"},
+	{"webhook_results.json", "webhook_results_test.json"},
 }
 
 var writeOutput bool
@@ -74,6 +77,7 @@ func readFile(prefix string, filename string) ([]byte, error) {
 
 type runResult struct {
 	assetCache *assets.AssetCache
+	config     flows.EngineConfig
 	session    flows.Session
 	outputs    []*Output
 }
@@ -100,7 +104,11 @@ func runFlow(assetsFilename string, triggerEnvelope *utils.TypedEnvelope, caller
 		return runResult{}, fmt.Errorf("Error reading test assets '%s': %s", assetsFilename, err)
 	}
 
-	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient)
+	// all sessions use a fixed clock so that our outputs contain predictable timestamps
+	clock := utils.NewFixedClock(time.Date(2018, 7, 6, 12, 30, 0, 123456789, time.UTC))
+	config := engine.NewConfigBuilder().WithClock(clock).Build()
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
 
 	trigger, err := triggers.ReadTrigger(session, triggerEnvelope)
 	if err != nil {
@@ -123,7 +131,7 @@ func runFlow(assetsFilename string, triggerEnvelope *utils.TypedEnvelope, caller
 		}
 		outputs = append(outputs, &Output{sessionJSON, marshalEventLog(session.Events())})
 
-		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, sessionJSON)
+		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, sessionJSON)
 		if err != nil {
 			return runResult{}, fmt.Errorf("Error marshalling output: %s", err)
 		}
@@ -144,7 +152,7 @@ func runFlow(assetsFilename string, triggerEnvelope *utils.TypedEnvelope, caller
 	}
 	outputs = append(outputs, &Output{sessionJSON, marshalEventLog(session.Events())})
 
-	return runResult{assetCache, session, outputs}, nil
+	return runResult{assetCache, config, session, outputs}, nil
 }
 
 func TestFlows(t *testing.T) {
@@ -152,14 +160,11 @@ func TestFlows(t *testing.T) {
 	require.NoError(t, err)
 
 	defer server.Close()
-	defer utils.SetUUIDGenerator(utils.DefaultUUIDGenerator)
 
 	// save away our server URL so we can rewrite our URLs
 	serverURL = server.URL
 
 	for _, tc := range flowTests {
-		utils.SetUUIDGenerator(utils.NewSeededUUID4Generator(123456))
-
 		testJSON, err := readFile("flows/", tc.output)
 		require.NoError(t, err, "Error reading output file for flow '%s' and output '%s': %s", tc.assets, tc.output, err)
 
@@ -200,9 +205,12 @@ func TestFlows(t *testing.T) {
 			testJSON, err := utils.JSONMarshalPretty(flowTest)
 			require.NoError(t, err, "Error marshalling test definition: %s", err)
 
+			testJSON, err = normalizeJSON(testJSON)
+			require.NoError(t, err, "Error normalizing test definition: %s", err)
+
 			// write our output
 			outputFilename := deriveFilename("flows/", tc.output)
-			err = ioutil.WriteFile(outputFilename, clearTimestamps(testJSON), 0644)
+			err = ioutil.WriteFile(outputFilename, testJSON, 0644)
 			require.NoError(t, err, "Error writing test file to %s: %s", outputFilename, err)
 		} else {
 			// start by checking we have the expected number of outputs
@@ -229,6 +237,18 @@ func TestFlows(t *testing.T) {
 					}
 				}
 			}
+
+			// finally check that replaying the recorded caller events generates the same events, using the same config so
+			// that timestamps which end up in webhook payloads also match
+			_, recorded, err := flowTest.readEvents()
+			require.NoError(t, err, "error reading recorded events for flow test %s", tc.assets)
+
+			_, calls, err := engine.Replay(runResult.assetCache, assets.NewMockAssetServer(), runResult.config, test.TestHTTPClient, runResult.session.Trigger(), callerEvents, recorded)
+			require.NoError(t, err, "error replaying flow test %s", tc.assets)
+
+			for c, call := range calls {
+				assert.True(t, call.Matches(), "replayed call[%d] generated different events for flow test %s", c, tc.assets)
+			}
 		}
 	}
 }
@@ -249,8 +269,6 @@ func assertEqualJSON(t *testing.T, expected json.RawMessage, actual json.RawMess
 }
 
 func normalizeJSON(data json.RawMessage) ([]byte, error) {
-	data = clearTimestamps(data)
-
 	var asMap map[string]interface{}
 	if err := json.Unmarshal(data, &asMap); err != nil {
 		return nil, err
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/nyaruka/ezconf"
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/engine"
@@ -8,33 +10,57 @@ import (
 
 // Config is our top level config for our flowserver
 type Config struct {
-	Port                          int    `help:"the port we will run on"`
-	LogLevel                      string `help:"the logging level to use"`
-	Static                        string `help:""`
-	AssetCacheSize                int64  `help:"the maximum size of our asset cache"`
-	AssetCachePrune               int    `help:"the number of assets to prune when we reach our max size"`
-	AssetServerToken              string `help:"the token to use when authentication to the asset server"`
-	EngineDisableWebhooks         bool   `help:"whether to disable webhook calls from the engine"`
-	EngineMaxWebhookResponseBytes int    `help:"the maximum allowed byte size of webhook responses"`
-	SentryDSN                     string `help:"the DSN for reporting errors to Sentry"`
-	Version                       string `help:"the version to use in request and response headers"`
+	Port                              int    `help:"the port we will run on"`
+	LogLevel                          string `help:"the logging level to use"`
+	Static                            string `help:""`
+	AssetCacheSize                    int64  `help:"the maximum size of our asset cache"`
+	AssetCachePrune                   int    `help:"the number of assets to prune when we reach our max size"`
+	AssetServerToken                  string `help:"the token to use when authentication to the asset server"`
+	EngineDisableWebhooks             bool   `help:"whether to disable webhook calls from the engine"`
+	EngineMaxWebhookResponseBytes     int    `help:"the maximum allowed byte size of webhook responses"`
+	EngineMaxStepsPerCall             int    `help:"the maximum number of steps the engine will take in a single start or resume call"`
+	EngineMaxRunsPerSession           int    `help:"the maximum number of runs that a single session can contain"`
+	EngineMaxSubflowDepth             int    `help:"the maximum depth of nested subflows"`
+	EngineMaxNodeVisitsPerCall        int    `help:"the maximum number of times a node can be visited in a single start or resume call"`
+	EngineAllowLoopsWithExternalCalls bool   `help:"whether loops which call a webhook or resthook are allowed to exceed the maximum node visits"`
+	EngineMaxUSSDMsgLength            int    `help:"the maximum length of messages sent to USSD channels, after which they are split into pages"`
+	EngineWebhookRetryBackoffMS       int    `help:"the delay in milliseconds before the first retry of a failed webhook call, which doubles for each retry after that"`
+	SentryDSN                         string `help:"the DSN for reporting errors to Sentry"`
+	Version                           string `help:"the version to use in request and response headers"`
 }
 
 func (c *Config) Engine() flows.EngineConfig {
-	return engine.NewConfig(c.EngineDisableWebhooks, nil, c.EngineMaxWebhookResponseBytes)
+	return engine.NewConfigBuilder().
+		WithDisableWebhooks(c.EngineDisableWebhooks).
+		WithMaxWebhookResponseBytes(c.EngineMaxWebhookResponseBytes).
+		WithMaxStepsPerCall(c.EngineMaxStepsPerCall).
+		WithMaxRunsPerSession(c.EngineMaxRunsPerSession).
+		WithMaxSubflowDepth(c.EngineMaxSubflowDepth).
+		WithMaxNodeVisitsPerCall(c.EngineMaxNodeVisitsPerCall).
+		WithAllowLoopsWithExternalCalls(c.EngineAllowLoopsWithExternalCalls).
+		WithMaxUSSDMsgLength(c.EngineMaxUSSDMsgLength).
+		WithWebhookRetryBackoff(time.Duration(c.EngineWebhookRetryBackoffMS) * time.Millisecond).
+		Build()
 }
 
 // NewDefaultConfig returns our default configuration
 func NewDefaultConfig() *Config {
 	return &Config{
-		Port:                          8800,
-		LogLevel:                      "info",
-		AssetCacheSize:                1000,
-		AssetCachePrune:               100,
-		AssetServerToken:              "missing_temba_token",
-		EngineDisableWebhooks:         false,
-		EngineMaxWebhookResponseBytes: 10000,
-		Version: "Dev",
+		Port:                              8800,
+		LogLevel:                          "info",
+		AssetCacheSize:                    1000,
+		AssetCachePrune:                   100,
+		AssetServerToken:                  "missing_temba_token",
+		EngineDisableWebhooks:             false,
+		EngineMaxWebhookResponseBytes:     10000,
+		EngineMaxStepsPerCall:             100,
+		EngineMaxRunsPerSession:           50,
+		EngineMaxSubflowDepth:             10,
+		EngineMaxNodeVisitsPerCall:        1,
+		EngineAllowLoopsWithExternalCalls: false,
+		EngineMaxUSSDMsgLength:            182,
+		EngineWebhookRetryBackoffMS:       1000,
+		Version:                           "Dev",
 	}
 }
 
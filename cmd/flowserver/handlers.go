@@ -80,7 +80,7 @@ func (s *FlowServer) handleStart(w http.ResponseWriter, r *http.Request) (interf
 	}
 
 	// start our flow
-	err = session.Start(trigger, callerEvents)
+	err = session.StartContext(r.Context(), trigger, callerEvents)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +147,7 @@ func (s *FlowServer) handleResume(w http.ResponseWriter, r *http.Request) (inter
 	}
 
 	// resume our flow
-	err = session.Resume(callerEvents)
+	err = session.ResumeContext(r.Context(), callerEvents)
 	if err != nil {
 		return nil, err
 	}
@@ -827,24 +827,24 @@ func Mod(env utils.Environment, num1 types.XNumber, num2 types.XNumber) types.XV
 
 // Rand returns a single random number between [0.0-1.0).
 //
-//   @(rand()) -> 0.3849275689214193274523267973563633859157562255859375
-//   @(rand()) -> 0.607552015674623913099594574305228888988494873046875
+//   @(rand()) -> 0.1916813832661861116690715789445675909519195556640625
+//   @(rand()) -> 0.299846285007636825792332047058152966201305389404296875
 //
 // @function rand()
 func Rand(env utils.Environment) types.XValue {
-	return types.NewXNumber(utils.RandDecimal())
+	return types.NewXNumber(utils.RandDecimal(env.Rand()))
 }
 
 // RandBetween a single random integer in the given inclusive range.
 //
-//   @(rand_between(1, 10)) -> 5
-//   @(rand_between(1, 10)) -> 10
+//   @(rand_between(1, 10)) -> 6
+//   @(rand_between(1, 10)) -> 3
 //
 // @function rand_between()
 func RandBetween(env utils.Environment, min types.XNumber, max types.XNumber) types.XValue {
 	span := (max.Native().Sub(min.Native())).Add(decimal.New(1, 0))
 
-	val := utils.RandDecimal().Mul(span).Add(min.Native()).Floor()
+	val := utils.RandDecimal(env.Rand()).Mul(span).Add(min.Native()).Floor()
 
 	return types.NewXNumber(val)
 }
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nyaruka/goflow/utils"
+)
+
+// XXMLElement is an element of a parsed XML document. Its child elements and attributes can be resolved by name, with
+// repeated child elements resolving to an array, and it reduces to its text content.
+type XXMLElement struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*XXMLElement
+}
+
+// Describe returns a representation of this type for error messages
+func (x *XXMLElement) Describe() string { return "xml element" }
+
+// Name returns the local name of this element
+func (x *XXMLElement) Name() string { return x.name }
+
+// Text returns the text content of this element with surrounding whitespace removed
+func (x *XXMLElement) Text() string { return strings.TrimSpace(x.text) }
+
+// Length returns the number of child elements
+func (x *XXMLElement) Length() int { return len(x.children) }
+
+// Resolve resolves the given key to child elements with that name, or failing that, an attribute with that name
+func (x *XXMLElement) Resolve(env utils.Environment, key string) XValue {
+	children := x.childrenNamed(key)
+	if len(children) == 1 {
+		return children[0]
+	} else if len(children) > 1 {
+		return NewXArray(children...)
+	}
+
+	for _, attr := range x.attrs {
+		if attr.Name.Local == key {
+			return NewXText(attr.Value)
+		}
+	}
+
+	return NewXResolveError(x, key)
+}
+
+// Reduce is called when this object needs to be reduced to a primitive
+func (x *XXMLElement) Reduce(env utils.Environment) XPrimitive { return NewXText(x.Text()) }
+
+// ToXJSON is called when this type is passed to @(json(...))
+func (x *XXMLElement) ToXJSON(env utils.Environment) XText {
+	// elements which are just text are represented as strings
+	if len(x.attrs) == 0 && len(x.children) == 0 {
+		return MustMarshalToXText(x.Text())
+	}
+
+	values := make(map[string]XValue, len(x.attrs)+len(x.children)+1)
+	for _, attr := range x.attrs {
+		values[attr.Name.Local] = NewXText(attr.Value)
+	}
+	for _, child := range x.children {
+		values[child.name] = x.Resolve(env, child.name)
+	}
+	if _, hasText := values["text"]; !hasText && x.Text() != "" {
+		values["text"] = NewXText(x.Text())
+	}
+
+	return NewXMap(values).ToXJSON(env)
+}
+
+func (x *XXMLElement) childrenNamed(name string) []XValue {
+	children := make([]XValue, 0)
+	for _, child := range x.children {
+		if child.name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+var _ XValue = (*XXMLElement)(nil)
+var _ XLengthable = (*XXMLElement)(nil)
+var _ XResolvable = (*XXMLElement)(nil)
+
+// XMLToXValue parses the given XML document, returning its root element
+func XMLToXValue(data []byte) XValue {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *XXMLElement
+	stack := make([]*XXMLElement, 0)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return NewXError(err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &XXMLElement{name: t.Name.Local, attrs: t.Attr}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			} else if root == nil {
+				root = element
+			} else {
+				return NewXError(fmt.Errorf("XML document has more than one root element"))
+			}
+
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return NewXError(fmt.Errorf("XML document has no root element"))
+	}
+	return root
+}
@@ -0,0 +1,74 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/excellent"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXXMLElement(t *testing.T) {
+	env := utils.NewDefaultEnvironment()
+
+	root := types.XMLToXValue([]byte(`<?xml version="1.0"?><response status="ok"><state>WA</state><city>Seattle</city><city>Spokane</city></response>`)).(*types.XXMLElement)
+	assert.Equal(t, "response", root.Name())
+	assert.Equal(t, "", root.Text())
+	assert.Equal(t, 3, root.Length())
+	assert.Equal(t, "xml element", root.Describe())
+	assert.Equal(t, types.NewXText(`{"city":["Seattle","Spokane"],"state":"WA","status":"ok"}`), root.ToXJSON(env))
+
+	leaf := types.XMLToXValue([]byte(`<price currency="USD"> 12.50 </price>`)).(*types.XXMLElement)
+	assert.Equal(t, types.NewXText("12.50"), leaf.Reduce(env))
+	assert.Equal(t, types.NewXText(`{"currency":"USD","text":"12.50"}`), leaf.ToXJSON(env))
+
+	assert.Equal(t, types.NewXText(`"hello"`), types.XMLToXValue([]byte(`<greeting>hello</greeting>`)).ToXJSON(env))
+
+	// things which aren't XML documents
+	for _, data := range []string{``, `hello`, `{"foo": "bar"}`, `<a><b></a>`, `<a/><b/>`} {
+		assert.True(t, types.IsXError(types.XMLToXValue([]byte(data))), "expected error parsing '%s'", data)
+	}
+}
+
+func TestXXMLElementResolve(t *testing.T) {
+	doc := []byte(`<response status="ok">
+		<state>WA</state>
+		<results>
+			<result id="1"><name>Bob</name></result>
+			<result id="2"><name>Jim</name></result>
+		</results>
+		<count>2</count>
+	</response>`)
+
+	var xmlTests = []struct {
+		lookup   string
+		expected string
+		hasError bool
+	}{
+		{"state", "WA", false},
+		{"status", "ok", false},
+		{"count", "2", false},
+		{"results.result.0.name", "Bob", false},
+		{"results.result.1.id", "2", false},
+		{"results.result.1.name", "Jim", false},
+
+		{"zip", "", true},
+		{"results.result.2", "", true},
+		{"state.foo", "", true},
+	}
+
+	env := utils.NewDefaultEnvironment()
+	for _, test := range xmlTests {
+		value := excellent.ResolveValue(env, types.XMLToXValue(doc), test.lookup)
+		err, _ := value.(error)
+
+		if test.hasError {
+			assert.Error(t, err, "expected error resolving '%s'", test.lookup)
+		} else {
+			assert.NoError(t, err, "unexpected error resolving '%s'", test.lookup)
+			assert.Equal(t, types.NewXText(test.expected), value.Reduce(env), "value mismatch resolving '%s'", test.lookup)
+		}
+	}
+}
@@ -38,6 +38,20 @@ func NewBaseAction(uuid flows.ActionUUID) BaseAction {
 // UUID returns the UUID of the action
 func (a *BaseAction) UUID() flows.ActionUUID { return a.UUID_ }
 
+// AllowedFlowTypes returns the flow types which this action is allowed to occur in, which by default is all of them
+func (a *BaseAction) AllowedFlowTypes() []flows.FlowType { return flows.AllFlowTypes }
+
+// ExternalCaller is implemented by action types which can make calls to external services, e.g. webhooks
+type ExternalCaller interface {
+	MakesExternalCall() bool
+}
+
+// MakesExternalCall returns whether the given action makes calls to external services
+func MakesExternalCall(action flows.Action) bool {
+	caller, isCaller := action.(ExternalCaller)
+	return isCaller && caller.MakesExternalCall()
+}
+
 func (a *BaseAction) evaluateLocalizableTemplate(run flows.FlowRun, localizationKey string, defaultValue string) (string, error) {
 	localizedTemplate := run.GetText(utils.UUID(a.UUID()), localizationKey, defaultValue)
 	return run.EvaluateTemplateAsString(localizedTemplate, false)
@@ -187,6 +201,27 @@ func (a *BaseAction) evaluateMessage(run flows.FlowRun, languages utils.Language
 	return evaluatedText, evaluatedAttachments, evaluatedQuickReplies
 }
 
+// helper function for voice actions which creates a message to be spoken or played to the contact on the current call
+func (a *BaseAction) createIVRMsg(run flows.FlowRun, text string, attachments []flows.Attachment, log flows.EventLog) error {
+	channelSet, err := run.Session().Assets().GetChannelSet()
+	if err != nil {
+		return err
+	}
+
+	// the call will be on the first URN which has a channel that can make calls
+	for _, u := range run.Contact().URNs() {
+		channel := channelSet.GetForURNAndRole(u, flows.ChannelRoleCall)
+		if channel != nil {
+			msg := flows.NewMsgOut(flows.MsgUUID(run.Session().NewUUID()), u.URN, channel, text, attachments, nil)
+			log.Add(events.NewIVRCreatedEvent(msg))
+			return nil
+		}
+	}
+
+	log.Add(events.NewErrorEvent(fmt.Errorf("no channel which can make calls for contact")))
+	return nil
+}
+
 func (a *BaseAction) resolveContactsAndGroups(run flows.FlowRun, step flows.Step, actionURNs []urns.URN, actionContacts []*flows.ContactReference, actionGroups []*flows.GroupReference, actionLegacyVars []string, log flows.EventLog) ([]urns.URN, []*flows.ContactReference, []*flows.GroupReference, error) {
 	// copy URNs
 	urnList := make([]urns.URN, 0, len(actionURNs))
@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+// TypeCallClassifier is the type for the call classifier action
+const TypeCallClassifier string = "call_classifier"
+
+// categories of the results created by call classifier actions
+const (
+	classifierCategorySuccess = "Success"
+	classifierCategoryFailure = "Failure"
+)
+
+// CallClassifierAction can be used to classify the intent and entities of some input using an NLU classifier. The
+// input field may be a template and will be evaluated at runtime. The classification is done by the classification
+// service of the session, which by default identifies intents using the keywords defined on the classifier.
+//
+// A `run_result_changed` event will be created with the top intent as its value and the full classification of
+// intents and entities as its extra data, which can be tested with [has_intent](#test:has_intent) and
+// [has_top_intent](#test:has_top_intent).
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "call_classifier",
+//     "classifier": {
+//       "uuid": "1c06c884-39dd-4ce4-ad9f-9a01cbe6c000",
+//       "name": "Booking"
+//     },
+//     "input": "@run.input.text",
+//     "result_name": "Intent"
+//   }
+//
+// @action call_classifier
+type CallClassifierAction struct {
+	BaseAction
+	Classifier *flows.ClassifierReference `json:"classifier" validate:"required"`
+	Input      string                     `json:"input" validate:"required"`
+	ResultName string                     `json:"result_name" validate:"required"`
+}
+
+// Type returns the type of this action
+func (a *CallClassifierAction) Type() string { return TypeCallClassifier }
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *CallClassifierAction) Validate(assets flows.SessionAssets) error {
+	_, err := assets.GetClassifier(a.Classifier.UUID)
+	return err
+}
+
+// Execute runs this action
+func (a *CallClassifierAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	classifier, err := run.Session().Assets().GetClassifier(a.Classifier.UUID)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+		return nil
+	}
+
+	input, err := run.EvaluateTemplateAsString(a.Input, false)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+	if input == "" {
+		log.Add(events.NewErrorEvent(fmt.Errorf("call_classifier input evaluated to empty string, skipping")))
+		return nil
+	}
+
+	service := run.Session().ClassificationService()
+	if service == nil {
+		log.Add(events.NewErrorEvent(fmt.Errorf("no classification service available for classifier '%s'", classifier.Name())))
+		return nil
+	}
+
+	classification, err := service.Classify(run.Session(), classifier, input)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+		log.Add(events.NewRunResultChangedEvent(a.ResultName, "", classifierCategoryFailure, "", step.NodeUUID(), &input, nil))
+		return nil
+	}
+
+	extra, err := json.Marshal(classification)
+	if err != nil {
+		return err
+	}
+
+	value := ""
+	if top := classification.TopIntent(); top != nil {
+		value = top.Name
+	}
+
+	log.Add(events.NewRunResultChangedEvent(a.ResultName, value, classifierCategorySuccess, "", step.NodeUUID(), &input, extra))
+	return nil
+}
@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+// TypeCallResthook is the type for the call resthook action
+const TypeCallResthook string = "call_resthook"
+
+// CallResthookAction can be used to call a resthook, which POSTs the default webhook payload to every URL which is
+// subscribed to it. One of those calls is then inserted in the @run.webhook context variable so that routers can
+// test it with [has_webhook_status](#test:has_webhook_status). That will be the first successful call if there was
+// one, otherwise the last failed call.
+//
+// A `webhook_called` event will be created for each subscriber. Subscribers which respond with a 410 Gone have
+// unsubscribed from the resthook, and their calls are only used as @run.webhook if every subscriber responded
+// that way.
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "call_resthook",
+//     "resthook": "new-registration"
+//   }
+//
+// @action call_resthook
+type CallResthookAction struct {
+	BaseAction
+	Resthook string `json:"resthook" validate:"required"`
+}
+
+// Type returns the type of this action
+func (a *CallResthookAction) Type() string { return TypeCallResthook }
+
+// MakesExternalCall returns true as this action calls external services
+func (a *CallResthookAction) MakesExternalCall() bool { return true }
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *CallResthookAction) Validate(assets flows.SessionAssets) error {
+	_, err := assets.GetResthook(a.Resthook)
+	return err
+}
+
+// Execute runs this action
+func (a *CallResthookAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	resthook, err := run.Session().Assets().GetResthook(a.Resthook)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+		return nil
+	}
+
+	// build our payload, which is the same for every subscriber
+	body, err := run.EvaluateTemplateAsString(flows.DefaultWebhookPayload, false)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+
+	webhooks := make([]*flows.WebhookCall, 0, len(resthook.Subscribers()))
+
+	for _, url := range resthook.Subscribers() {
+		req, err := http.NewRequest("POST", url, strings.NewReader(body))
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+			continue
+		}
+		req = req.WithContext(run.Session().Context())
+		req.Header.Add("Content-Type", "application/json")
+
+		webhook, err := flows.MakeWebhookCall(run.Session(), req)
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+		}
+		if webhook != nil {
+			log.Add(events.NewWebhookCalledEvent(webhook, a.Resthook))
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	run.SetWebhook(pickResthookResult(webhooks))
+	return nil
+}
+
+// picks which of the calls made to the subscribers of a resthook should be used as @run.webhook
+func pickResthookResult(webhooks []*flows.WebhookCall) *flows.WebhookCall {
+	var lastFailure, lastGone *flows.WebhookCall
+
+	for _, webhook := range webhooks {
+		if webhook.Status() == flows.WebhookStatusSuccess {
+			return webhook
+		}
+		if webhook.StatusCode() == http.StatusGone {
+			lastGone = webhook
+		} else {
+			lastFailure = webhook
+		}
+	}
+
+	if lastFailure != nil {
+		return lastFailure
+	}
+	return lastGone
+}
@@ -1,12 +1,19 @@
 package actions
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/utils"
 )
 
 // TypeCallWebhook is the type for the call webhook action
@@ -15,32 +22,74 @@ const TypeCallWebhook string = "call_webhook"
 // CallWebhookAction can be used to call an external service and insert the results in @run.webhook
 // context variable. The body, header and url fields may be templates and will be evaluated at runtime.
 //
-// A `webhook_called` event will be created based on the results of the HTTP call.
+// Instead of a body, a form of keys and values can be given, in which case the values are evaluated as templates and
+// sent as a form-encoded body. The `Content-Type` header of such requests is `application/x-www-form-urlencoded` unless
+// a header says otherwise.
+//
+// Each attempt can be limited to a timeout in seconds, and calls which fail with a connection error or a 5xx response
+// can be retried, waiting longer before each retry. If a secret is given, the body is signed with that secret using
+// HMAC-SHA256 and the signature is sent in the `X-Webhook-Signature` header.
+//
+// Values can be extracted from a successful JSON response and saved as run results, by mapping result names to paths
+// like `results[0].state`. A result can have a default, which may be empty, that is used if there is no value at its
+// path. Results are only saved if every one of them has a value, in which case a `run_result_changed` event is created
+// for each.
+//
+// A `webhook_called` event will be created based on the results of each attempt at the HTTP call.
 //
 //   {
 //     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
 //     "type": "call_webhook",
-//     "method": "GET",
+//     "method": "POST",
 //     "url": "http://localhost:49998/?cmd=success",
 //     "headers": {
 //       "Authorization": "Token AAFFZZHH"
+//     },
+//     "body": "{\"contact\": \"@contact.uuid\"}",
+//     "timeout": 10,
+//     "retries": 2,
+//     "secret": "Partner Key",
+//     "results": {
+//       "OK": {"path": "ok"},
+//       "State": {"path": "results[0].state", "default": "unknown"}
 //     }
 //   }
 //
 // @action call_webhook
 type CallWebhookAction struct {
 	BaseAction
-	Method  string            `json:"method"             validate:"required,http_method"`
-	URL     string            `json:"url"                validate:"required"`
-	Headers map[string]string `json:"headers,omitempty"`
-	Body    string            `json:"body,omitempty"`
+	Method  string                    `json:"method"             validate:"required,http_method"`
+	URL     string                    `json:"url"                validate:"required"`
+	Headers map[string]string         `json:"headers,omitempty"`
+	Body    string                    `json:"body,omitempty"`
+	Form    map[string]string         `json:"form,omitempty"`
+	Timeout int                       `json:"timeout,omitempty"  validate:"omitempty,min=1,max=15"`
+	Retries int                       `json:"retries,omitempty"  validate:"omitempty,min=0,max=5"`
+	Secret  string                    `json:"secret,omitempty"`
+	Results map[string]*WebhookResult `json:"results,omitempty"  validate:"dive"`
+}
+
+// WebhookResult describes a value to be extracted from the JSON response of a webhook and saved as a run result
+type WebhookResult struct {
+	Path    string  `json:"path"              validate:"required,json_path"`
+	Default *string `json:"default,omitempty"`
 }
 
 // Type returns the type of this action
 func (a *CallWebhookAction) Type() string { return TypeCallWebhook }
 
+// MakesExternalCall returns true as this action calls external services
+func (a *CallWebhookAction) MakesExternalCall() bool { return true }
+
 // Validate validates our action is valid and has all the assets it needs
 func (a *CallWebhookAction) Validate(assets flows.SessionAssets) error {
+	if a.Body != "" && len(a.Form) > 0 {
+		return fmt.Errorf("can't specify both a body and a form")
+	}
+	if a.Secret != "" {
+		_, err := assets.GetSecret(a.Secret)
+		return err
+	}
 	return nil
 }
 
@@ -67,12 +116,18 @@ func (a *CallWebhookAction) Execute(run flows.FlowRun, step flows.Step, log flow
 		}
 	}
 
-	// build our request
+	// or build a form-encoded body
+	if len(a.Form) > 0 {
+		body = a.encodeForm(run, log)
+	}
+
+	// build our request, bound to the context of the current call so it is abandoned if that call is cancelled
 	req, err := http.NewRequest(method, url, strings.NewReader(body))
 	if err != nil {
 		log.Add(events.NewErrorEvent(err))
 		return nil
 	}
+	req = req.WithContext(run.Session().Context())
 
 	// add the custom headers, substituting any template vars
 	for key, value := range a.Headers {
@@ -84,14 +139,103 @@ func (a *CallWebhookAction) Execute(run flows.FlowRun, step flows.Step, log flow
 		req.Header.Add(key, headerValue)
 	}
 
-	webhook, err := flows.MakeWebhookCall(run.Session(), req)
+	if len(a.Form) > 0 && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
-	if err != nil {
-		log.Add(events.NewErrorEvent(err))
-	} else {
-		log.Add(events.NewWebhookCalledEvent(webhook.URL(), webhook.Status(), webhook.StatusCode(), webhook.Request(), webhook.Response()))
+	// sign our body if we have a secret
+	if a.Secret != "" {
+		secret, err := run.Session().Assets().GetSecret(a.Secret)
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+			return nil
+		}
+
+		req.Header.Set(webhookSignatureHeader, signWebhookBody(secret.Value(), body))
 	}
 
+	timeout := time.Duration(a.Timeout) * time.Second
+
+	webhook, _ := flows.MakeWebhookCallWithRetries(run.Session(), req, timeout, a.Retries, func(attempt *flows.WebhookCall, err error) {
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+		}
+		if attempt != nil {
+			log.Add(events.NewWebhookCalledEvent(attempt, ""))
+		}
+	})
+
 	run.SetWebhook(webhook)
+
+	a.saveResults(run, step, webhook, log)
 	return nil
 }
+
+// evaluates the values of our form and encodes it as a form-encoded body
+func (a *CallWebhookAction) encodeForm(run flows.FlowRun, log flows.EventLog) string {
+	form := url.Values{}
+	for key, value := range a.Form {
+		formValue, err := run.EvaluateTemplateAsString(value, false)
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+		}
+		form.Set(key, formValue)
+	}
+	return form.Encode()
+}
+
+// extracts our results from the given webhook response and saves them, but only if all of them can be extracted
+func (a *CallWebhookAction) saveResults(run flows.FlowRun, step flows.Step, webhook *flows.WebhookCall, log flows.EventLog) {
+	if len(a.Results) == 0 {
+		return
+	}
+
+	// only extract values from successful responses, otherwise our defaults are used
+	var body []byte
+	if webhook != nil && webhook.Status() == flows.WebhookStatusSuccess {
+		body = []byte(webhook.Body())
+	}
+
+	names := make([]string, 0, len(a.Results))
+	for name := range a.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resultEvents := make([]flows.Event, 0, len(names))
+
+	for _, name := range names {
+		result := a.Results[name]
+
+		path, err := utils.ParseJSONPath(result.Path)
+		if err != nil {
+			log.Add(events.NewErrorEvent(err))
+			return
+		}
+
+		value, found := path.Lookup(body)
+		if !found {
+			if result.Default == nil {
+				log.Add(events.NewErrorEvent(fmt.Errorf("no value found at path '%s' for result '%s', no results saved", result.Path, name)))
+				return
+			}
+			value = *result.Default
+		}
+
+		resultEvents = append(resultEvents, events.NewRunResultChangedEvent(name, value, "", "", step.NodeUUID(), nil, nil))
+	}
+
+	for _, event := range resultEvents {
+		log.Add(event)
+	}
+}
+
+// the header used to send the signature of signed webhook bodies
+const webhookSignatureHeader = "X-Webhook-Signature"
+
+// signs the given webhook body with the given secret, returning a signature like sha256=<hex encoded HMAC>
+func signWebhookBody(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -7,45 +7,46 @@ import (
 	"github.com/nyaruka/goflow/utils"
 )
 
-func ActionFromEnvelope(envelope *utils.TypedEnvelope) (flows.Action, error) {
-	var action flows.Action
+var registeredTypes = map[string](func() flows.Action){}
+
+// RegisterType registers a new type of action so that it can be read from flow definitions. The given function
+// should return a new empty instance of the action type which is then populated from JSON.
+func RegisterType(name string, initFunc func() flows.Action) {
+	registeredTypes[name] = initFunc
+}
 
-	switch envelope.Type {
-	case TypeAddInputLabels:
-		action = &AddInputLabelsAction{}
-	case TypeAddContactGroups:
-		action = &AddContactGroupsAction{}
-	case TypeAddContactURN:
-		action = &AddContactURNAction{}
-	case TypeCallWebhook:
-		action = &CallWebhookAction{}
-	case TypeRemoveContactGroups:
-		action = &RemoveContactGroupsAction{}
-	case TypeSendBroadcast:
-		action = &SendBroadcastAction{}
-	case TypeSendEmail:
-		action = &SendEmailAction{}
-	case TypeSendMsg:
-		action = &SendMsgAction{}
-	case TypeSetContactChannel:
-		action = &SetContactChannelAction{}
-	case TypeSetContactField:
-		action = &SetContactFieldAction{}
-	case TypeSetContactLanguage:
-		action = &SetContactLanguageAction{}
-	case TypeSetContactName:
-		action = &SetContactNameAction{}
-	case TypeSetContactTimezone:
-		action = &SetContactTimezoneAction{}
-	case TypeSetRunResult:
-		action = &SetRunResultAction{}
-	case TypeStartFlow:
-		action = &StartFlowAction{}
-	case TypeStartSession:
-		action = &StartSessionAction{}
-	default:
+func init() {
+	RegisterType(TypeAddInputLabels, func() flows.Action { return &AddInputLabelsAction{} })
+	RegisterType(TypeAddContactGroups, func() flows.Action { return &AddContactGroupsAction{} })
+	RegisterType(TypeAddContactURN, func() flows.Action { return &AddContactURNAction{} })
+	RegisterType(TypeCallClassifier, func() flows.Action { return &CallClassifierAction{} })
+	RegisterType(TypeCallResthook, func() flows.Action { return &CallResthookAction{} })
+	RegisterType(TypeCallWebhook, func() flows.Action { return &CallWebhookAction{} })
+	RegisterType(TypeOpenTicket, func() flows.Action { return &OpenTicketAction{} })
+	RegisterType(TypePlayAudio, func() flows.Action { return &PlayAudioAction{} })
+	RegisterType(TypeRemoveContactGroups, func() flows.Action { return &RemoveContactGroupsAction{} })
+	RegisterType(TypeSayMsg, func() flows.Action { return &SayMsgAction{} })
+	RegisterType(TypeSendBroadcast, func() flows.Action { return &SendBroadcastAction{} })
+	RegisterType(TypeSendEmail, func() flows.Action { return &SendEmailAction{} })
+	RegisterType(TypeSendMsg, func() flows.Action { return &SendMsgAction{} })
+	RegisterType(TypeSetContactChannel, func() flows.Action { return &SetContactChannelAction{} })
+	RegisterType(TypeSetContactField, func() flows.Action { return &SetContactFieldAction{} })
+	RegisterType(TypeSetContactLanguage, func() flows.Action { return &SetContactLanguageAction{} })
+	RegisterType(TypeSetContactName, func() flows.Action { return &SetContactNameAction{} })
+	RegisterType(TypeSetContactTimezone, func() flows.Action { return &SetContactTimezoneAction{} })
+	RegisterType(TypeSetRunResult, func() flows.Action { return &SetRunResultAction{} })
+	RegisterType(TypeStartFlow, func() flows.Action { return &StartFlowAction{} })
+	RegisterType(TypeStartSession, func() flows.Action { return &StartSessionAction{} })
+	RegisterType(TypeTransferAirtime, func() flows.Action { return &TransferAirtimeAction{} })
+}
+
+// ActionFromEnvelope attempts to build an action of a registered type from the passed in TypedEnvelope
+func ActionFromEnvelope(envelope *utils.TypedEnvelope) (flows.Action, error) {
+	initFunc := registeredTypes[envelope.Type]
+	if initFunc == nil {
 		return nil, fmt.Errorf("unknown action type: %s", envelope.Type)
 	}
 
+	action := initFunc()
 	return action, utils.UnmarshalAndValidate(envelope.Data, action, fmt.Sprintf("action[type=%s]", envelope.Type))
 }
@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+// TypeOpenTicket is the type for the open ticket action
+const TypeOpenTicket string = "open_ticket"
+
+// OpenTicketAction can be used to open a ticket in a ticketer so that a human agent can take over the conversation
+// with the contact. The subject and body fields may be templates and will be evaluated at runtime. The ticket is
+// available in the context as @run.ticket, and if the node has a `ticket` wait, the run will pause until the ticket
+// is closed.
+//
+// A `ticket_opened` event will be created with the new ticket.
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "open_ticket",
+//     "ticketer": {
+//       "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+//       "name": "Support Tickets"
+//     },
+//     "subject": "Help needed by @contact.name",
+//     "body": "@run.input.text"
+//   }
+//
+// @action open_ticket
+type OpenTicketAction struct {
+	BaseAction
+	Ticketer *flows.TicketerReference `json:"ticketer" validate:"required"`
+	Subject  string                   `json:"subject" validate:"required"`
+	Body     string                   `json:"body"`
+}
+
+// Type returns the type of this action
+func (a *OpenTicketAction) Type() string { return TypeOpenTicket }
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *OpenTicketAction) Validate(assets flows.SessionAssets) error {
+	_, err := assets.GetTicketer(a.Ticketer.UUID)
+	return err
+}
+
+// Execute runs this action
+func (a *OpenTicketAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	if run.Contact() == nil {
+		log.Add(events.NewFatalErrorEvent(fmt.Errorf("can't execute action in session without a contact")))
+		return nil
+	}
+
+	ticketer, err := run.Session().Assets().GetTicketer(a.Ticketer.UUID)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+		return nil
+	}
+
+	subject, err := run.EvaluateTemplateAsString(a.Subject, false)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+	body, err := run.EvaluateTemplateAsString(a.Body, false)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+
+	ticket := flows.NewTicket(flows.TicketUUID(run.Session().NewUUID()), ticketer.Reference(), subject, body)
+
+	log.Add(events.NewTicketOpenedEvent(ticket))
+	return nil
+}
@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+// TypePlayAudio is the type for the play audio action
+const TypePlayAudio string = "play_audio"
+
+// PlayAudioAction can be used to play an audio recording to the contact on the current call of a voice flow. The
+// audio URL field may contain templates and is localizable, e.g. to play a recording of a previous result.
+//
+// An `ivr_created` event will be created with the evaluated URL as an audio attachment.
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "play_audio",
+//     "audio_url": "http://uploads.temba.io/2353262.m4a"
+//   }
+//
+// @action play_audio
+type PlayAudioAction struct {
+	BaseAction
+	AudioURL string `json:"audio_url" validate:"required"`
+}
+
+// Type returns the type of this action
+func (a *PlayAudioAction) Type() string { return TypePlayAudio }
+
+// AllowedFlowTypes returns the flow types which this action is allowed to occur in
+func (a *PlayAudioAction) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeVoice}
+}
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *PlayAudioAction) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// Execute runs this action
+func (a *PlayAudioAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	if run.Contact() == nil {
+		log.Add(events.NewFatalErrorEvent(fmt.Errorf("can't execute action in session without a contact")))
+		return nil
+	}
+
+	evaluatedURL, err := a.evaluateLocalizableTemplate(run, "audio_url", a.AudioURL)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+	if evaluatedURL == "" {
+		log.Add(events.NewErrorEvent(fmt.Errorf("play_audio audio URL evaluated to empty string, skipping")))
+		return nil
+	}
+
+	return a.createIVRMsg(run, "", []flows.Attachment{flows.Attachment(fmt.Sprintf("audio:%s", evaluatedURL))}, log)
+}
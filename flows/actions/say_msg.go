@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+// TypeSayMsg is the type for the say message action
+const TypeSayMsg string = "say_msg"
+
+// SayMsgAction can be used to speak a message to the contact on the current call of a voice flow. The text field
+// may contain templates and is localizable.
+//
+// An `ivr_created` event will be created with the evaluated text.
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "say_msg",
+//     "text": "Hi @contact.name, please enter your PIN followed by the hash key"
+//   }
+//
+// @action say_msg
+type SayMsgAction struct {
+	BaseAction
+	Text string `json:"text" validate:"required"`
+}
+
+// Type returns the type of this action
+func (a *SayMsgAction) Type() string { return TypeSayMsg }
+
+// AllowedFlowTypes returns the flow types which this action is allowed to occur in
+func (a *SayMsgAction) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeVoice}
+}
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *SayMsgAction) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// Execute runs this action
+func (a *SayMsgAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	if run.Contact() == nil {
+		log.Add(events.NewFatalErrorEvent(fmt.Errorf("can't execute action in session without a contact")))
+		return nil
+	}
+
+	evaluatedText, err := a.evaluateLocalizableTemplate(run, "text", a.Text)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+	}
+	if evaluatedText == "" {
+		log.Add(events.NewErrorEvent(fmt.Errorf("say_msg text evaluated to empty string, skipping")))
+		return nil
+	}
+
+	return a.createIVRMsg(run, evaluatedText, nil, log)
+}
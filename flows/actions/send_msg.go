@@ -2,6 +2,7 @@ package actions
 
 import (
 	"fmt"
+
 	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/events"
@@ -12,7 +13,8 @@ const TypeSendMsg string = "send_msg"
 
 // SendMsgAction can be used to reply to the current contact in a flow. The text field may contain templates.
 //
-// A `broadcast_created` event will be created with the evaluated text.
+// A `msg_created` event will be created with the evaluated text. If the message is sent on a USSD channel then
+// its quick replies are rendered as a numbered menu, and it is split into pages if it doesn't fit on a single screen.
 //
 //   {
 //     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
@@ -39,6 +41,11 @@ type msgDestination struct {
 // Type returns the type of this action
 func (a *SendMsgAction) Type() string { return TypeSendMsg }
 
+// AllowedFlowTypes returns the flow types which this action is allowed to occur in
+func (a *SendMsgAction) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeMessaging}
+}
+
 // Validate validates our action is valid and has all the assets it needs
 func (a *SendMsgAction) Validate(assets flows.SessionAssets) error {
 	return nil
@@ -74,7 +81,20 @@ func (a *SendMsgAction) Execute(run flows.FlowRun, step flows.Step, log flows.Ev
 
 	// create a new message for each URN+channel destination
 	for _, dest := range destinations {
-		msg := flows.NewMsgOut(dest.urn, dest.channel, evaluatedText, evaluatedAttachments, evaluatedQuickReplies)
+		// USSD channels can only show a single screen so quick replies become a menu which may need to be paged
+		if dest.channel.HasRole(flows.ChannelRoleUSSD) {
+			pages := flows.PageUSSDMsg(evaluatedText, evaluatedQuickReplies, run.Session().EngineConfig().MaxUSSDMsgLength())
+			msg := flows.NewMsgOut(flows.MsgUUID(run.Session().NewUUID()), dest.urn, dest.channel, pages[0], evaluatedAttachments, nil)
+
+			if len(pages) > 1 {
+				log.Add(events.NewPagedMsgCreatedEvent(msg, 1, pages))
+			} else {
+				log.Add(events.NewMsgCreatedEvent(msg))
+			}
+			continue
+		}
+
+		msg := flows.NewMsgOut(flows.MsgUUID(run.Session().NewUUID()), dest.urn, dest.channel, evaluatedText, evaluatedAttachments, evaluatedQuickReplies)
 		log.Add(events.NewMsgCreatedEvent(msg))
 	}
 
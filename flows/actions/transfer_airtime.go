@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+
+	"github.com/shopspring/decimal"
+)
+
+// TypeTransferAirtime is the type for the transfer airtime action
+const TypeTransferAirtime string = "transfer_airtime"
+
+// categories of the results created by transfer airtime actions
+const (
+	airtimeCategorySuccess = "Success"
+	airtimeCategoryFailure = "Failure"
+)
+
+// TransferAirtimeAction can be used to transfer airtime to the first phone number of the contact. The amounts to
+// transfer are given for each currency, and the airtime service of the session picks the amount in the currency
+// of the contact's network. The transfer is available in the context as @run.airtime and can be tested with
+// [has_airtime_status](#test:has_airtime_status).
+//
+// An `airtime_transferred` event will be created with the status of the transfer and the amount actually
+// transferred. If a result name is given, a `run_result_changed` event will also be created with that amount as
+// its value.
+//
+//   {
+//     "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//     "type": "transfer_airtime",
+//     "amounts": {"USD": 0.5, "RWF": 500},
+//     "result_name": "Reward Transfer"
+//   }
+//
+// @action transfer_airtime
+type TransferAirtimeAction struct {
+	BaseAction
+	Amounts    map[string]decimal.Decimal `json:"amounts" validate:"required,min=1"`
+	ResultName string                     `json:"result_name,omitempty"`
+}
+
+// Type returns the type of this action
+func (a *TransferAirtimeAction) Type() string { return TypeTransferAirtime }
+
+// Validate validates our action is valid and has all the assets it needs
+func (a *TransferAirtimeAction) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// Execute runs this action
+func (a *TransferAirtimeAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	contact := run.Contact()
+	if contact == nil {
+		log.Add(events.NewFatalErrorEvent(fmt.Errorf("can't execute action in session without a contact")))
+		return nil
+	}
+
+	telURNs := contact.URNs().WithScheme(urns.TelScheme)
+	if len(telURNs) == 0 {
+		log.Add(events.NewErrorEvent(fmt.Errorf("can't transfer airtime to contact without a phone number")))
+		return nil
+	}
+	recipient := telURNs.RawURNs(false)[0]
+
+	service := run.Session().AirtimeService()
+	if service == nil {
+		log.Add(events.NewErrorEvent(fmt.Errorf("no airtime service available to transfer airtime")))
+		return nil
+	}
+
+	transfer, err := service.Transfer(run.Session(), recipient, a.Amounts)
+	if err != nil {
+		log.Add(events.NewErrorEvent(err))
+
+		// services may not be able to tell us anything about a failed transfer
+		if transfer == nil {
+			transfer = &flows.AirtimeTransfer{Recipient: recipient, Status: flows.AirtimeTransferStatusFailed}
+		}
+		transfer.Status = flows.AirtimeTransferStatusFailed
+	}
+
+	log.Add(events.NewAirtimeTransferredEvent(transfer))
+
+	if a.ResultName != "" {
+		category := airtimeCategorySuccess
+		if transfer.Status != flows.AirtimeTransferStatusSuccess {
+			category = airtimeCategoryFailure
+		}
+		log.Add(events.NewRunResultChangedEvent(a.ResultName, transfer.ActualAmount.String(), category, "", step.NodeUUID(), nil, nil))
+	}
+	return nil
+}
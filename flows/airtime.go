@@ -0,0 +1,74 @@
+package flows
+
+import (
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// AirtimeTransferStatus is the status of an airtime transfer
+type AirtimeTransferStatus string
+
+// different statuses of airtime transfers
+const (
+	AirtimeTransferStatusSuccess AirtimeTransferStatus = "success"
+	AirtimeTransferStatusFailed  AirtimeTransferStatus = "failed"
+)
+
+// AirtimeTransfer is the result of trying to transfer airtime to a contact. It renders as the amount actually
+// transferred in a template, and has the following properties which can be accessed:
+//
+//  * `recipient` the URN of the recipient of the transfer
+//  * `currency` the currency of the amount transferred
+//  * `desired_amount` the amount that we tried to transfer
+//  * `actual_amount` the amount that was actually transferred
+//  * `status` the status of the transfer, one of `success` or `failed`
+type AirtimeTransfer struct {
+	Recipient     urns.URN              `json:"recipient"`
+	Currency      string                `json:"currency"`
+	DesiredAmount decimal.Decimal       `json:"desired_amount"`
+	ActualAmount  decimal.Decimal       `json:"actual_amount"`
+	Status        AirtimeTransferStatus `json:"status"`
+}
+
+// AirtimeService is a provider which can transfer airtime to a recipient. It is given the amounts to transfer in
+// each currency and should pick the one which matches the currency of the recipient's network.
+type AirtimeService interface {
+	Transfer(session Session, recipient urns.URN, amounts map[string]decimal.Decimal) (*AirtimeTransfer, error)
+}
+
+// Resolve resolves the given key when this transfer is referenced in an expression
+func (t *AirtimeTransfer) Resolve(env utils.Environment, key string) types.XValue {
+	switch key {
+	case "recipient":
+		return types.NewXText(string(t.Recipient))
+	case "currency":
+		return types.NewXText(t.Currency)
+	case "desired_amount":
+		return types.NewXNumber(t.DesiredAmount)
+	case "actual_amount":
+		return types.NewXNumber(t.ActualAmount)
+	case "status":
+		return types.NewXText(string(t.Status))
+	}
+
+	return types.NewXResolveError(t, key)
+}
+
+// Describe returns a representation of this type for error messages
+func (t *AirtimeTransfer) Describe() string { return "airtime transfer" }
+
+// Reduce is called when this object needs to be reduced to a primitive
+func (t *AirtimeTransfer) Reduce(env utils.Environment) types.XPrimitive {
+	return types.NewXNumber(t.ActualAmount)
+}
+
+// ToXJSON is called when this type is passed to @(json(...))
+func (t *AirtimeTransfer) ToXJSON(env utils.Environment) types.XText {
+	return types.ResolveKeys(env, t, "recipient", "currency", "desired_amount", "actual_amount", "status").ToXJSON(env)
+}
+
+var _ types.XValue = (*AirtimeTransfer)(nil)
+var _ types.XResolvable = (*AirtimeTransfer)(nil)
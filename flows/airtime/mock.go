@@ -0,0 +1,45 @@
+package airtime
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+
+	"github.com/shopspring/decimal"
+)
+
+// MockService is an airtime service which pretends that every recipient is on a network which uses the same
+// currency, and that every transfer in that currency succeeds. It's intended for testing flows which transfer
+// airtime without calling a real provider.
+type MockService struct {
+	currency string
+}
+
+// NewMockService creates a new mock airtime service which transfers airtime in the given currency
+func NewMockService(currency string) *MockService {
+	return &MockService{currency: currency}
+}
+
+// Transfer pretends to transfer the amount in our currency to the given recipient
+func (s *MockService) Transfer(session flows.Session, recipient urns.URN, amounts map[string]decimal.Decimal) (*flows.AirtimeTransfer, error) {
+	transfer := &flows.AirtimeTransfer{
+		Recipient:     recipient,
+		Currency:      s.currency,
+		DesiredAmount: decimal.Zero,
+		ActualAmount:  decimal.Zero,
+		Status:        flows.AirtimeTransferStatusFailed,
+	}
+
+	amount, hasAmount := amounts[s.currency]
+	if !hasAmount {
+		return transfer, fmt.Errorf("no amount configured for transfers in %s", s.currency)
+	}
+
+	transfer.DesiredAmount = amount
+	transfer.ActualAmount = amount
+	transfer.Status = flows.AirtimeTransferStatusSuccess
+	return transfer, nil
+}
+
+var _ flows.AirtimeService = (*MockService)(nil)
@@ -0,0 +1,32 @@
+package airtime_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/airtime"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockService(t *testing.T) {
+	service := airtime.NewMockService("RWF")
+	recipient := urns.URN("tel:+250788123123")
+
+	transfer, err := service.Transfer(nil, recipient, map[string]decimal.Decimal{"USD": decimal.RequireFromString("0.5"), "RWF": decimal.RequireFromString("500")})
+	assert.NoError(t, err)
+	assert.Equal(t, recipient, transfer.Recipient)
+	assert.Equal(t, "RWF", transfer.Currency)
+	assert.Equal(t, "500", transfer.DesiredAmount.String())
+	assert.Equal(t, "500", transfer.ActualAmount.String())
+	assert.Equal(t, flows.AirtimeTransferStatusSuccess, transfer.Status)
+
+	// no amount in our currency means the transfer fails
+	transfer, err = service.Transfer(nil, recipient, map[string]decimal.Decimal{"USD": decimal.RequireFromString("0.5")})
+	assert.EqualError(t, err, "no amount configured for transfers in RWF")
+	assert.Equal(t, "RWF", transfer.Currency)
+	assert.Equal(t, "0", transfer.ActualAmount.String())
+	assert.Equal(t, flows.AirtimeTransferStatusFailed, transfer.Status)
+}
@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"testing"
@@ -18,10 +19,10 @@ func TestAssetCache(t *testing.T) {
 	}]`))
 	cache := NewAssetCache(100, 10)
 
-	asset, err := cache.GetAsset(server, assetType("pizza"), "")
+	asset, err := cache.GetAsset(context.Background(), server, assetType("pizza"), "")
 	assert.EqualError(t, err, "asset type 'pizza' not supported by asset server")
 
-	asset, err = cache.GetAsset(server, assetTypeLabelSet, "")
+	asset, err = cache.GetAsset(context.Background(), server, assetTypeLabelSet, "")
 	assert.NoError(t, err)
 	assert.Equal(t, server.MockedRequests(), []string{"http://testserver/assets/label/"})
 
@@ -30,9 +31,17 @@ func TestAssetCache(t *testing.T) {
 	assert.NotNil(t, labelSet.FindByName("Spam"))
 
 	// check that we can refetch without making another server request
-	asset, err = cache.GetAsset(server, assetTypeLabelSet, "")
+	asset, err = cache.GetAsset(context.Background(), server, assetTypeLabelSet, "")
 	assert.NoError(t, err)
 	assert.Equal(t, server.MockedRequests(), []string{"http://testserver/assets/label/"})
+
+	// check that we don't make a server request for an asset if our context has been cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	asset, err = cache.GetAsset(ctx, server, assetTypeFieldSet, "")
+	assert.EqualError(t, err, "asset request cancelled: context canceled")
+	assert.Equal(t, server.MockedRequests(), []string{"http://testserver/assets/label/"})
 }
 
 func TestAssetServer(t *testing.T) {
@@ -58,7 +67,7 @@ func TestAssetServer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "http://testserver/assets/flow/2aad21f6-30b7-42c5-bd7f-1b720c154817/", url)
 
-	asset, err := server.fetchAsset(url, assetTypeFlow)
+	asset, err := server.fetchAsset(context.Background(), url, assetTypeFlow)
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"http://testserver/assets/flow/2aad21f6-30b7-42c5-bd7f-1b720c154817/"}, server.mockedRequests)
 
@@ -76,7 +85,7 @@ func TestSessionAssets(t *testing.T) {
 		}
 	]`)
 	cache := NewAssetCache(100, 10)
-	sessionAssets := NewSessionAssets(cache, server)
+	sessionAssets := NewSessionAssets(context.Background(), cache, server)
 
 	group, err := sessionAssets.GetGroup(flows.GroupUUID("2aad21f6-30b7-42c5-bd7f-1b720c154817"))
 	assert.NoError(t, err)
@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -18,11 +19,15 @@ type assetType string
 
 const (
 	assetTypeChannelSet        assetType = "channel_set"
+	assetTypeClassifierSet     assetType = "classifier_set"
 	assetTypeFieldSet          assetType = "field_set"
 	assetTypeFlow              assetType = "flow"
 	assetTypeGroupSet          assetType = "group_set"
 	assetTypeLabelSet          assetType = "label_set"
 	assetTypeLocationHierarchy assetType = "location_hierarchy"
+	assetTypeResthookSet       assetType = "resthook_set"
+	assetTypeSecretSet         assetType = "secret_set"
+	assetTypeTicketerSet       assetType = "ticketer_set"
 )
 
 // AssetCache fetches and caches assets for the engine
@@ -55,18 +60,19 @@ func (c *AssetCache) addAsset(url string, asset interface{}) {
 	c.cache.Set(c.normalizeURL(url), asset, time.Hour*24)
 }
 
-// GetAsset gets an asset from the cache if it's there or from the asset server
-func (c *AssetCache) GetAsset(server AssetServer, itemType assetType, itemUUID string) (interface{}, error) {
+// GetAsset gets an asset from the cache if it's there or from the asset server. The given context controls the
+// deadline and cancellation of any request made to the asset server.
+func (c *AssetCache) GetAsset(ctx context.Context, server AssetServer, itemType assetType, itemUUID string) (interface{}, error) {
 	url, err := server.getAssetURL(itemType, itemUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.getAsset(url, server, itemType)
+	return c.getAsset(ctx, url, server, itemType)
 }
 
 // gets an asset from the cache if it's there or from the asset server
-func (c *AssetCache) getAsset(url string, server AssetServer, itemType assetType) (interface{}, error) {
+func (c *AssetCache) getAsset(ctx context.Context, url string, server AssetServer, itemType assetType) (interface{}, error) {
 	item := c.cache.Get(c.normalizeURL(url))
 
 	// asset was in cache, so just return it
@@ -84,8 +90,13 @@ func (c *AssetCache) getAsset(url string, server AssetServer, itemType assetType
 		return item.Value(), nil
 	}
 
+	// don't bother fetching if our caller has already given up
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("asset request cancelled: %s", err)
+	}
+
 	// actually fetch the asset from it's URL
-	fetched, err := server.fetchAsset(url, itemType)
+	fetched, err := server.fetchAsset(ctx, url, itemType)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +148,8 @@ func readAsset(data json.RawMessage, itemType assetType) (interface{}, error) {
 		assetReader = func(data json.RawMessage) (interface{}, error) { return utils.ReadLocationHierarchy(data) }
 	} else if itemType == assetTypeChannelSet {
 		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadChannelSet(data) }
+	} else if itemType == assetTypeClassifierSet {
+		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadClassifierSet(data) }
 	} else if itemType == assetTypeFieldSet {
 		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadFieldSet(data) }
 	} else if itemType == assetTypeFlow {
@@ -145,6 +158,12 @@ func readAsset(data json.RawMessage, itemType assetType) (interface{}, error) {
 		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadGroupSet(data) }
 	} else if itemType == assetTypeLabelSet {
 		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadLabelSet(data) }
+	} else if itemType == assetTypeResthookSet {
+		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadResthookSet(data) }
+	} else if itemType == assetTypeSecretSet {
+		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadSecretSet(data) }
+	} else if itemType == assetTypeTicketerSet {
+		assetReader = func(data json.RawMessage) (interface{}, error) { return flows.ReadTicketerSet(data) }
 	} else {
 		return nil, fmt.Errorf("unsupported asset type: %s", itemType)
 	}
@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -16,7 +17,7 @@ import (
 type AssetServer interface {
 	isTypeSupported(assetType) bool
 	getAssetURL(assetType, string) (string, error)
-	fetchAsset(string, assetType) (interface{}, error)
+	fetchAsset(context.Context, string, assetType) (interface{}, error)
 }
 
 type assetServer struct {
@@ -65,11 +66,12 @@ func (s *assetServer) getAssetURL(itemType assetType, itemUUID string) (string,
 }
 
 // fetches an asset by its URL and parses it as the provided type
-func (s *assetServer) fetchAsset(url string, itemType assetType) (interface{}, error) {
+func (s *assetServer) fetchAsset(ctx context.Context, url string, itemType assetType) (interface{}, error) {
 	request, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
+	request = request.WithContext(ctx)
 
 	// set request headers
 	request.Header.Set("Authorization", fmt.Sprintf("Token %s", s.authToken))
@@ -113,11 +115,15 @@ func NewMockAssetServer() *MockAssetServer {
 		assetServer: assetServer{
 			typeURLs: map[assetType]string{
 				assetTypeChannelSet:        "http://testserver/assets/channel/",
+				assetTypeClassifierSet:     "http://testserver/assets/classifier/",
 				assetTypeFieldSet:          "http://testserver/assets/field/",
 				assetTypeFlow:              "http://testserver/assets/flow/{uuid}/",
 				assetTypeGroupSet:          "http://testserver/assets/group/",
 				assetTypeLabelSet:          "http://testserver/assets/label/",
 				assetTypeLocationHierarchy: "http://testserver/assets/location_hierarchy/",
+				assetTypeResthookSet:       "http://testserver/assets/resthook/",
+				assetTypeSecretSet:         "http://testserver/assets/secret/",
+				assetTypeTicketerSet:       "http://testserver/assets/ticketer/",
 			},
 		},
 		mockResponses:  map[string]json.RawMessage{},
@@ -133,7 +139,7 @@ func (s *MockAssetServer) MockedRequests() []string {
 	return s.mockedRequests
 }
 
-func (s *MockAssetServer) fetchAsset(url string, itemType assetType) (interface{}, error) {
+func (s *MockAssetServer) fetchAsset(ctx context.Context, url string, itemType assetType) (interface{}, error) {
 	s.mockedRequests = append(s.mockedRequests, url)
 
 	assetBuf, found := s.mockResponses[url]
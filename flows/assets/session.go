@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/nyaruka/goflow/flows"
@@ -9,15 +10,17 @@ import (
 
 // our implementation of SessionAssets - the high-level API for asset access from the engine
 type sessionAssets struct {
+	ctx    context.Context
 	cache  *AssetCache
 	server AssetServer
 }
 
 var _ flows.SessionAssets = (*sessionAssets)(nil)
 
-// NewSessionAssets creates a new session assets instance with the provided base URLs
-func NewSessionAssets(cache *AssetCache, server AssetServer) flows.SessionAssets {
-	return &sessionAssets{cache: cache, server: server}
+// NewSessionAssets creates a new session assets instance with the provided base URLs. Any assets which need to be
+// fetched from the asset server are fetched within the given context.
+func NewSessionAssets(ctx context.Context, cache *AssetCache, server AssetServer) flows.SessionAssets {
+	return &sessionAssets{ctx: ctx, cache: cache, server: server}
 }
 
 // HasLocations returns whether locations are supported as an asset item type
@@ -27,7 +30,7 @@ func (s *sessionAssets) HasLocations() bool {
 
 // GetLocationHierarchy gets the location hierarchy asset for the session
 func (s *sessionAssets) GetLocationHierarchy() (*utils.LocationHierarchy, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeLocationHierarchy, "")
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeLocationHierarchy, "")
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +56,7 @@ func (s *sessionAssets) GetChannel(uuid flows.ChannelUUID) (flows.Channel, error
 
 // GetChannelSet gets the set of all channels asset for the session
 func (s *sessionAssets) GetChannelSet() (*flows.ChannelSet, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeChannelSet, "")
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeChannelSet, "")
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +67,32 @@ func (s *sessionAssets) GetChannelSet() (*flows.ChannelSet, error) {
 	return channels, nil
 }
 
+// GetClassifier gets an NLU classifier asset for the session
+func (s *sessionAssets) GetClassifier(uuid flows.ClassifierUUID) (flows.Classifier, error) {
+	classifiers, err := s.GetClassifierSet()
+	if err != nil {
+		return nil, err
+	}
+	classifier := classifiers.FindByUUID(uuid)
+	if classifier == nil {
+		return nil, fmt.Errorf("no such classifier with uuid '%s'", uuid)
+	}
+	return classifier, nil
+}
+
+// GetClassifierSet gets the set of all NLU classifiers asset for the session
+func (s *sessionAssets) GetClassifierSet() (*flows.ClassifierSet, error) {
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeClassifierSet, "")
+	if err != nil {
+		return nil, err
+	}
+	classifiers, isType := asset.(*flows.ClassifierSet)
+	if !isType {
+		return nil, fmt.Errorf("asset cache contains asset with wrong type")
+	}
+	return classifiers, nil
+}
+
 // GetField gets a contact field asset for the session
 func (s *sessionAssets) GetField(key string) (*flows.Field, error) {
 	fields, err := s.GetFieldSet()
@@ -79,7 +108,7 @@ func (s *sessionAssets) GetField(key string) (*flows.Field, error) {
 
 // GetFieldSet gets the set of all fields asset for the session
 func (s *sessionAssets) GetFieldSet() (*flows.FieldSet, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeFieldSet, "")
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeFieldSet, "")
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +121,7 @@ func (s *sessionAssets) GetFieldSet() (*flows.FieldSet, error) {
 
 // GetFlow gets a flow asset for the session
 func (s *sessionAssets) GetFlow(uuid flows.FlowUUID) (flows.Flow, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeFlow, string(uuid))
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeFlow, string(uuid))
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +147,7 @@ func (s *sessionAssets) GetGroup(uuid flows.GroupUUID) (*flows.Group, error) {
 
 // GetGroupSet gets the set of all groups asset for the session
 func (s *sessionAssets) GetGroupSet() (*flows.GroupSet, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeGroupSet, "")
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeGroupSet, "")
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +172,7 @@ func (s *sessionAssets) GetLabel(uuid flows.LabelUUID) (*flows.Label, error) {
 }
 
 func (s *sessionAssets) GetLabelSet() (*flows.LabelSet, error) {
-	asset, err := s.cache.GetAsset(s.server, assetTypeLabelSet, "")
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeLabelSet, "")
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +182,81 @@ func (s *sessionAssets) GetLabelSet() (*flows.LabelSet, error) {
 	}
 	return labels, nil
 }
+
+// GetResthook gets a resthook asset for the session
+func (s *sessionAssets) GetResthook(slug string) (*flows.Resthook, error) {
+	resthooks, err := s.GetResthookSet()
+	if err != nil {
+		return nil, err
+	}
+	resthook := resthooks.FindBySlug(slug)
+	if resthook == nil {
+		return nil, fmt.Errorf("no such resthook with slug '%s'", slug)
+	}
+	return resthook, nil
+}
+
+// GetResthookSet gets the set of all resthooks asset for the session
+func (s *sessionAssets) GetResthookSet() (*flows.ResthookSet, error) {
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeResthookSet, "")
+	if err != nil {
+		return nil, err
+	}
+	resthooks, isType := asset.(*flows.ResthookSet)
+	if !isType {
+		return nil, fmt.Errorf("asset cache contains asset with wrong type")
+	}
+	return resthooks, nil
+}
+
+// GetSecret gets a secret asset for the session
+func (s *sessionAssets) GetSecret(name string) (*flows.Secret, error) {
+	secrets, err := s.GetSecretSet()
+	if err != nil {
+		return nil, err
+	}
+	secret := secrets.FindByName(name)
+	if secret == nil {
+		return nil, fmt.Errorf("no such secret with name '%s'", name)
+	}
+	return secret, nil
+}
+
+// GetSecretSet gets the set of all secrets asset for the session
+func (s *sessionAssets) GetSecretSet() (*flows.SecretSet, error) {
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeSecretSet, "")
+	if err != nil {
+		return nil, err
+	}
+	secrets, isType := asset.(*flows.SecretSet)
+	if !isType {
+		return nil, fmt.Errorf("asset cache contains asset with wrong type")
+	}
+	return secrets, nil
+}
+
+// GetTicketer gets a ticketer asset for the session
+func (s *sessionAssets) GetTicketer(uuid flows.TicketerUUID) (*flows.Ticketer, error) {
+	ticketers, err := s.GetTicketerSet()
+	if err != nil {
+		return nil, err
+	}
+	ticketer := ticketers.FindByUUID(uuid)
+	if ticketer == nil {
+		return nil, fmt.Errorf("no such ticketer with uuid '%s'", uuid)
+	}
+	return ticketer, nil
+}
+
+// GetTicketerSet gets the set of all ticketers asset for the session
+func (s *sessionAssets) GetTicketerSet() (*flows.TicketerSet, error) {
+	asset, err := s.cache.GetAsset(s.ctx, s.server, assetTypeTicketerSet, "")
+	if err != nil {
+		return nil, err
+	}
+	ticketers, isType := asset.(*flows.TicketerSet)
+	if !isType {
+		return nil, fmt.Errorf("asset cache contains asset with wrong type")
+	}
+	return ticketers, nil
+}
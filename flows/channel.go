@@ -152,6 +152,11 @@ func NewChannelSet(channels []Channel) *ChannelSet {
 
 // GetForURN returns the best channel for the given URN
 func (s *ChannelSet) GetForURN(urn *ContactURN) Channel {
+	return s.GetForURNAndRole(urn, ChannelRoleSend)
+}
+
+// GetForURNAndRole returns the best channel with the given role for the given URN
+func (s *ChannelSet) GetForURNAndRole(urn *ContactURN, role ChannelRole) Channel {
 	// if caller has told us which channel to use for this URN, use that
 	if urn.Channel() != nil {
 		return urn.Channel()
@@ -160,7 +165,7 @@ func (s *ChannelSet) GetForURN(urn *ContactURN) Channel {
 	// if not, return the first channel which supports this URN scheme
 	scheme := urn.Scheme()
 	for _, ch := range s.channels {
-		if ch.HasRole(ChannelRoleSend) && ch.SupportsScheme(scheme) {
+		if ch.HasRole(role) && ch.SupportsScheme(scheme) {
 			return ch
 		}
 	}
@@ -0,0 +1,149 @@
+package flows
+
+import (
+	"encoding/json"
+
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// Classifier is an NLU classifier which can identify the intents and entities in text. Besides the intents it knows
+// about, it can have keywords for each intent and values for each entity which are used by rule based classification
+// services that don't need training.
+type Classifier interface {
+	UUID() ClassifierUUID
+	Name() string
+	Intents() []string
+	Keywords() map[string][]string
+	Entities() map[string][]string
+	Reference() *ClassifierReference
+}
+
+type classifier struct {
+	uuid     ClassifierUUID
+	name     string
+	intents  []string
+	keywords map[string][]string
+	entities map[string][]string
+}
+
+// NewClassifier creates a new classifier
+func NewClassifier(uuid ClassifierUUID, name string, intents []string, keywords map[string][]string, entities map[string][]string) Classifier {
+	return &classifier{uuid: uuid, name: name, intents: intents, keywords: keywords, entities: entities}
+}
+
+// UUID returns the UUID of this classifier
+func (c *classifier) UUID() ClassifierUUID { return c.uuid }
+
+// Name returns the name of this classifier
+func (c *classifier) Name() string { return c.name }
+
+// Intents returns the names of the intents this classifier can identify
+func (c *classifier) Intents() []string { return c.intents }
+
+// Keywords returns the keywords for each intent of this classifier
+func (c *classifier) Keywords() map[string][]string { return c.keywords }
+
+// Entities returns the values for each entity of this classifier
+func (c *classifier) Entities() map[string][]string { return c.entities }
+
+// Reference returns a reference to this classifier
+func (c *classifier) Reference() *ClassifierReference {
+	return NewClassifierReference(c.uuid, c.name)
+}
+
+var _ Classifier = (*classifier)(nil)
+
+// ClassifierSet defines the unordered set of all classifiers for a session
+type ClassifierSet struct {
+	classifiers       []Classifier
+	classifiersByUUID map[ClassifierUUID]Classifier
+}
+
+// NewClassifierSet creates a new classifier set from the given slice of classifiers
+func NewClassifierSet(classifiers []Classifier) *ClassifierSet {
+	s := &ClassifierSet{classifiers: classifiers, classifiersByUUID: make(map[ClassifierUUID]Classifier, len(classifiers))}
+	for _, classifier := range s.classifiers {
+		s.classifiersByUUID[classifier.UUID()] = classifier
+	}
+	return s
+}
+
+// FindByUUID finds the classifier with the given UUID
+func (s *ClassifierSet) FindByUUID(uuid ClassifierUUID) Classifier {
+	return s.classifiersByUUID[uuid]
+}
+
+// ExtractedIntent is an intent identified by a classifier with the confidence of that identification
+type ExtractedIntent struct {
+	Name       string          `json:"name"`
+	Confidence decimal.Decimal `json:"confidence"`
+}
+
+// ExtractedEntity is a value of an entity identified by a classifier with the confidence of that identification
+type ExtractedEntity struct {
+	Value      string          `json:"value"`
+	Confidence decimal.Decimal `json:"confidence"`
+}
+
+// Classification is the result of classifying some text. Intents aren't necessarily ordered by confidence.
+type Classification struct {
+	Intents  []ExtractedIntent            `json:"intents"`
+	Entities map[string][]ExtractedEntity `json:"entities"`
+}
+
+// TopIntent returns the intent with the highest confidence or nil if no intents were identified
+func (c *Classification) TopIntent() *ExtractedIntent {
+	var top *ExtractedIntent
+	for i := range c.Intents {
+		if top == nil || c.Intents[i].Confidence.GreaterThan(top.Confidence) {
+			top = &c.Intents[i]
+		}
+	}
+	return top
+}
+
+// ClassificationService is a service which can classify text using a classifier, e.g. a cloud NLU service or a local
+// rule based implementation
+type ClassificationService interface {
+	Classify(Session, Classifier, string) (*Classification, error)
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type classifierEnvelope struct {
+	UUID     ClassifierUUID      `json:"uuid" validate:"required,uuid"`
+	Name     string              `json:"name"`
+	Intents  []string            `json:"intents" validate:"min=1"`
+	Keywords map[string][]string `json:"keywords,omitempty"`
+	Entities map[string][]string `json:"entities,omitempty"`
+}
+
+// ReadClassifier decodes a classifier from the passed in JSON
+func ReadClassifier(data json.RawMessage) (Classifier, error) {
+	ce := classifierEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, &ce, "classifier"); err != nil {
+		return nil, err
+	}
+
+	return NewClassifier(ce.UUID, ce.Name, ce.Intents, ce.Keywords, ce.Entities), nil
+}
+
+// ReadClassifierSet decodes classifiers from the passed in JSON
+func ReadClassifierSet(data json.RawMessage) (*ClassifierSet, error) {
+	items, err := utils.UnmarshalArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	classifiers := make([]Classifier, len(items))
+	for c := range items {
+		if classifiers[c], err = ReadClassifier(items[c]); err != nil {
+			return nil, err
+		}
+	}
+	return NewClassifierSet(classifiers), nil
+}
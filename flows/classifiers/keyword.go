@@ -0,0 +1,92 @@
+package classifiers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// KeywordService is a classification service which identifies intents by looking for their keywords in text, and
+// entities by looking for their values. It needs no training or external service so is useful for testing and for
+// small deployments.
+//
+// The confidence of an intent is the fraction of its keywords found in the text, and entity values are always
+// identified with full confidence.
+type KeywordService struct{}
+
+// NewKeywordService creates a new keyword classification service
+func NewKeywordService() *KeywordService {
+	return &KeywordService{}
+}
+
+// Classify classifies the given input using the keywords and entity values of the given classifier
+func (s *KeywordService) Classify(session flows.Session, classifier flows.Classifier, input string) (*flows.Classification, error) {
+	words := utils.TokenizeString(strings.ToLower(input))
+
+	classification := &flows.Classification{
+		Intents:  []flows.ExtractedIntent{},
+		Entities: make(map[string][]flows.ExtractedEntity),
+	}
+
+	for _, intent := range classifier.Intents() {
+		keywords := classifier.Keywords()[intent]
+		if len(keywords) == 0 {
+			continue
+		}
+
+		matched := 0
+		for _, keyword := range keywords {
+			if containsPhrase(words, keyword) {
+				matched++
+			}
+		}
+
+		if matched > 0 {
+			confidence := decimal.New(int64(matched), 0).DivRound(decimal.New(int64(len(keywords)), 0), 2)
+			classification.Intents = append(classification.Intents, flows.ExtractedIntent{Name: intent, Confidence: confidence})
+		}
+	}
+
+	// order intents by confidence so that the top intent is first
+	sort.SliceStable(classification.Intents, func(i, j int) bool {
+		return classification.Intents[i].Confidence.GreaterThan(classification.Intents[j].Confidence)
+	})
+
+	for entity, values := range classifier.Entities() {
+		for _, value := range values {
+			if containsPhrase(words, value) {
+				classification.Entities[entity] = append(classification.Entities[entity], flows.ExtractedEntity{Value: value, Confidence: decimal.New(1, 0)})
+			}
+		}
+	}
+
+	return classification, nil
+}
+
+// checks whether the given phrase appears as consecutive words in the given lowercase words
+func containsPhrase(words []string, phrase string) bool {
+	pins := utils.TokenizeString(strings.ToLower(phrase))
+	if len(pins) == 0 {
+		return false
+	}
+
+	for w := 0; w+len(pins) <= len(words); w++ {
+		matched := true
+		for p, pin := range pins {
+			if words[w+p] != pin {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+var _ flows.ClassificationService = (*KeywordService)(nil)
@@ -0,0 +1,49 @@
+package classifiers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/classifiers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordService(t *testing.T) {
+	classifier := flows.NewClassifier(
+		flows.ClassifierUUID("1c06c884-39dd-4ce4-ad9f-9a01cbe6c000"),
+		"Booking",
+		[]string{"greeting", "book_flight", "book_hotel", "cancel"},
+		map[string][]string{
+			"greeting":    {"hi", "hello"},
+			"book_flight": {"flight", "fly", "plane ticket"},
+			"book_hotel":  {"hotel", "room"},
+		},
+		map[string][]string{
+			"location": {"Paris", "New York", "Kigali"},
+		},
+	)
+
+	service := classifiers.NewKeywordService()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hi there", `{"intents":[{"name":"greeting","confidence":0.5}],"entities":{}}`},
+		{"I need a PLANE TICKET and a flight to New York, not Paris", `{"intents":[{"name":"book_flight","confidence":0.67}],"entities":{"location":[{"value":"Paris","confidence":1},{"value":"New York","confidence":1}]}}`},
+		{"hello! can I book a hotel room in kigali?", `{"intents":[{"name":"book_hotel","confidence":1},{"name":"greeting","confidence":0.5}],"entities":{"location":[{"value":"Kigali","confidence":1}]}}`},
+		{"a new plane", `{"intents":[],"entities":{}}`},
+	}
+
+	for _, tc := range tests {
+		classification, err := service.Classify(nil, classifier, tc.input)
+		require.NoError(t, err)
+
+		actual, err := json.Marshal(classification)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(actual), "classification mismatch for input '%s'", tc.input)
+	}
+}
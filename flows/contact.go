@@ -53,10 +53,11 @@ type Contact struct {
 	fields   FieldValues
 }
 
-// NewContact returns a new contact
-func NewContact(name string, language utils.Language, timezone *time.Location) *Contact {
+// NewContact returns a new contact with the given UUID, which callers creating contacts during a session should get
+// from that session so that it's deterministic
+func NewContact(uuid ContactUUID, name string, language utils.Language, timezone *time.Location) *Contact {
 	return &Contact{
-		uuid:     ContactUUID(utils.NewUUID()),
+		uuid:     uuid,
 		name:     name,
 		language: language,
 		timezone: timezone,
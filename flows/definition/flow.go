@@ -12,6 +12,7 @@ import (
 type flow struct {
 	uuid               flows.FlowUUID
 	name               string
+	flowType           flows.FlowType
 	revision           int
 	language           utils.Language
 	expireAfterMinutes int
@@ -27,10 +28,16 @@ type flow struct {
 
 type FlowObj = flow
 
-func NewFlow(uuid flows.FlowUUID, name string, revision int, language utils.Language, expireAfterMinutes int, localization flows.Localization, nodes []flows.Node, ui map[string]interface{}) (flows.Flow, error) {
+func NewFlow(uuid flows.FlowUUID, name string, flowType flows.FlowType, revision int, language utils.Language, expireAfterMinutes int, localization flows.Localization, nodes []flows.Node, ui map[string]interface{}) (flows.Flow, error) {
+	// flows are messaging flows unless otherwise specified
+	if flowType == "" {
+		flowType = flows.FlowTypeMessaging
+	}
+
 	f := &flow{
 		uuid:               uuid,
 		name:               name,
+		flowType:           flowType,
 		revision:           revision,
 		language:           language,
 		expireAfterMinutes: expireAfterMinutes,
@@ -65,6 +72,7 @@ func NewFlow(uuid flows.FlowUUID, name string, revision int, language utils.Lang
 
 func (f *flow) UUID() flows.FlowUUID                   { return f.uuid }
 func (f *flow) Name() string                           { return f.name }
+func (f *flow) Type() flows.FlowType                   { return f.flowType }
 func (f *flow) Revision() int                          { return f.revision }
 func (f *flow) Language() utils.Language               { return f.language }
 func (f *flow) ExpireAfterMinutes() int                { return f.expireAfterMinutes }
@@ -95,10 +103,19 @@ func (f *flow) Validate(assets flows.SessionAssets) error {
 			}
 			seenUUIDs[utils.UUID(action.UUID())] = true
 
+			if !isAllowedFlowType(action.AllowedFlowTypes(), f.flowType) {
+				return fmt.Errorf("action[uuid=%s, type=%s] is not allowed in flows of type %s", action.UUID(), action.Type(), f.flowType)
+			}
+
 			if err := action.Validate(assets); err != nil {
 				return fmt.Errorf("validation failed for action[uuid=%s, type=%s]: %v", action.UUID(), action.Type(), err)
 			}
 		}
+
+		// and its wait
+		if node.Wait() != nil && !isAllowedFlowType(node.Wait().AllowedFlowTypes(), f.flowType) {
+			return fmt.Errorf("wait[type=%s] on node %s is not allowed in flows of type %s", node.Wait().Type(), node.UUID(), f.flowType)
+		}
 	}
 	return err
 }
@@ -136,6 +153,16 @@ func (f *flow) Reference() *flows.FlowReference {
 	return flows.NewFlowReference(f.uuid, f.name)
 }
 
+// utility function to check whether the given flow type is one of the allowed ones
+func isAllowedFlowType(allowed []flows.FlowType, flowType flows.FlowType) bool {
+	for _, t := range allowed {
+		if t == flowType {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *flow) buildNodeMap() error {
 	f.nodeMap = make(map[flows.NodeUUID]flows.Node)
 
@@ -156,6 +183,7 @@ func (f *flow) buildNodeMap() error {
 type flowEnvelope struct {
 	UUID               flows.FlowUUID `json:"uuid"               validate:"required,uuid4"`
 	Name               string         `json:"name"               validate:"required"`
+	Type               flows.FlowType `json:"type,omitempty"     validate:"omitempty,eq=messaging|eq=voice"`
 	Revision           int            `json:"revision"`
 	Language           utils.Language `json:"language"`
 	ExpireAfterMinutes int            `json:"expire_after_minutes"`
@@ -179,7 +207,7 @@ func ReadFlow(data json.RawMessage) (flows.Flow, error) {
 		nodes[n] = envelope.Nodes[n]
 	}
 
-	return NewFlow(envelope.UUID, envelope.Name, envelope.Revision, envelope.Language, envelope.ExpireAfterMinutes, envelope.Localization, nodes, nil)
+	return NewFlow(envelope.UUID, envelope.Name, envelope.Type, envelope.Revision, envelope.Language, envelope.ExpireAfterMinutes, envelope.Localization, nodes, nil)
 }
 
 // MarshalJSON marshals this flow into JSON
@@ -195,6 +223,11 @@ func (f *flow) MarshalJSON() ([]byte, error) {
 		UI: f.ui,
 	}
 
+	// messaging is the default type so only write the type of other flows
+	if f.flowType != flows.FlowTypeMessaging {
+		fe.Type = f.flowType
+	}
+
 	if f.localization != nil {
 		fe.Localization = f.localization.(localization)
 	}
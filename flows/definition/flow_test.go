@@ -2,9 +2,11 @@ package definition_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
+	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/actions"
 	"github.com/nyaruka/goflow/flows/assets"
 	"github.com/nyaruka/goflow/flows/definition"
@@ -101,3 +103,69 @@ func TestFlowValidation(t *testing.T) {
 	// fix the set_contact_channel action
 	prefChannelAction.Channel.UUID = "57f1078f-88aa-46f4-a59a-948a5739c03d"
 }
+
+func TestFlowTypeValidation(t *testing.T) {
+	readFlow := func(flowType string, actionType string, actionField string, waitHint string) (flows.Flow, error) {
+		return definition.ReadFlow(json.RawMessage(fmt.Sprintf(`{
+			"uuid": "a9b7c5d3-1e2f-4a6b-8c0d-2e4f6a8b0c1d",
+			"name": "Call Me",
+			"type": "%s",
+			"nodes": [
+				{
+					"uuid": "b0c8d6e4-2f3a-4b7c-9d1e-3f5a7b9c1d2e",
+					"actions": [{"uuid": "c1d9e7f5-3a4b-4c8d-8e2f-4a6b8c0d2e3f", "type": "%s", "%s": "Hello"}],
+					"wait": {"type": "voice", "hint": {"type": "%s"}},
+					"exits": [{"uuid": "d2e0f8a6-4b5c-4d9e-9f3a-5b7c9d1e3f4a"}]
+				}
+			]
+		}`, flowType, actionType, actionField, waitHint)))
+	}
+
+	// voice flows can use voice actions
+	flow, err := readFlow("voice", "say_msg", "text", "digits")
+	require.NoError(t, err)
+	assert.Equal(t, flows.FlowTypeVoice, flow.Type())
+	assert.NoError(t, flow.Validate(nil))
+
+	flow, err = readFlow("voice", "play_audio", "audio_url", "audio")
+	require.NoError(t, err)
+	assert.NoError(t, flow.Validate(nil))
+
+	// but not messaging-only actions
+	flow, err = readFlow("voice", "send_msg", "text", "digits")
+	require.NoError(t, err)
+	assert.EqualError(t, flow.Validate(nil), "action[uuid=c1d9e7f5-3a4b-4c8d-8e2f-4a6b8c0d2e3f, type=send_msg] is not allowed in flows of type voice")
+
+	// and messaging flows can't use voice actions
+	flow, err = readFlow("messaging", "say_msg", "text", "digits")
+	require.NoError(t, err)
+	assert.EqualError(t, flow.Validate(nil), "action[uuid=c1d9e7f5-3a4b-4c8d-8e2f-4a6b8c0d2e3f, type=say_msg] is not allowed in flows of type messaging")
+
+	// waits are also restricted by flow type
+	flow, err = readFlow("messaging", "send_msg", "text", "digits")
+	require.NoError(t, err)
+	assert.EqualError(t, flow.Validate(nil), "wait[type=voice] on node b0c8d6e4-2f3a-4b7c-9d1e-3f5a7b9c1d2e is not allowed in flows of type messaging")
+
+	flow, err = definition.ReadFlow(json.RawMessage(`{
+		"uuid": "a9b7c5d3-1e2f-4a6b-8c0d-2e4f6a8b0c1d",
+		"name": "Call Me",
+		"type": "voice",
+		"nodes": [
+			{
+				"uuid": "b0c8d6e4-2f3a-4b7c-9d1e-3f5a7b9c1d2e",
+				"wait": {"type": "msg"},
+				"exits": [{"uuid": "d2e0f8a6-4b5c-4d9e-9f3a-5b7c9d1e3f4a"}]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.EqualError(t, flow.Validate(nil), "wait[type=msg] on node b0c8d6e4-2f3a-4b7c-9d1e-3f5a7b9c1d2e is not allowed in flows of type voice")
+
+	// voice waits can only collect digits or recordings
+	_, err = readFlow("voice", "say_msg", "text", "image")
+	assert.EqualError(t, err, "voice waits can only collect digits or audio, not image")
+
+	// and flows can only be messaging or voice
+	_, err = readFlow("ussd", "say_msg", "text", "digits")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// BatchResult is the result of starting a session for a single contact in a batch. If the session couldn't be started
+// then Err is set and Session and Events will be nil.
+type BatchResult struct {
+	Contact *flows.Contact
+	Session flows.Session
+	Events  []flows.Event
+	Err     error
+}
+
+// StartBatch starts a session in the given flow for each contact read from the contacts channel, using a pool of
+// workers which share the given asset cache so that assets like the flow itself are only fetched once. Results are
+// sent to the returned channel as each session is started, so won't be in the same order as the contacts. The results
+// channel is closed once the contacts channel has been closed and every contact read from it has been processed.
+//
+// Starting sessions is CPU bound unless they make calls to external services like webhooks, so using more workers than
+// there are cores only helps if the flow makes such calls.
+//
+// The given context applies to every session started. Contacts which are read after it has been cancelled are still
+// sent back as results but with its error.
+func StartBatch(ctx context.Context, assetCache *assets.AssetCache, assetServer assets.AssetServer, engineConfig flows.EngineConfig, httpClient *utils.HTTPClient, flow flows.Flow, env utils.Environment, contacts <-chan *flows.Contact, numWorkers int) <-chan *BatchResult {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	results := make(chan *BatchResult, numWorkers)
+	waitGroup := &sync.WaitGroup{}
+
+	for w := 0; w < numWorkers; w++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for contact := range contacts {
+				results <- startBatchSession(ctx, assetCache, assetServer, engineConfig, httpClient, flow, env, contact)
+			}
+		}()
+	}
+
+	// close our results channel once all our workers have finished
+	go func() {
+		waitGroup.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// starts a single session of a batch
+func startBatchSession(ctx context.Context, assetCache *assets.AssetCache, assetServer assets.AssetServer, engineConfig flows.EngineConfig, httpClient *utils.HTTPClient, flow flows.Flow, env utils.Environment, contact *flows.Contact) *BatchResult {
+	result := &BatchResult{Contact: contact}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	session := NewSession(assetCache, assetServer, engineConfig, httpClient).(*session)
+	trigger := triggers.NewManualTrigger(env, contact, flow, nil, session.Clock().Now().UTC())
+
+	if err := session.StartContext(ctx, trigger, nil); err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Session = session
+	result.Events = session.Events()
+	return result
+}
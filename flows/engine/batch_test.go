@@ -0,0 +1,126 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// creates an asset cache with the assets in the given file and the flow to start
+func batchTestAssets(t testing.TB, file string, flowUUID flows.FlowUUID) (*assets.AssetCache, assets.AssetServer, flows.Flow) {
+	sessionAssets, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	assetServer := assets.NewMockAssetServer()
+	flow, err := assets.NewSessionAssets(context.Background(), assetCache, assetServer).GetFlow(flowUUID)
+	require.NoError(t, err)
+
+	return assetCache, assetServer, flow
+}
+
+// sends the given number of contacts to a new channel which is closed after the last one
+func batchTestContacts(numContacts int) <-chan *flows.Contact {
+	contacts := make(chan *flows.Contact)
+	go func() {
+		for c := 0; c < numContacts; c++ {
+			contact := flows.NewContact(flows.ContactUUID(utils.NewUUID()), fmt.Sprintf("Contact %d", c), "eng", nil)
+			contact.AddURN(urns.URN(fmt.Sprintf("tel:+1800555%04d", c)))
+			contacts <- contact
+		}
+		close(contacts)
+	}()
+	return contacts
+}
+
+func TestStartBatch(t *testing.T) {
+	assetCache, assetServer, flow := batchTestAssets(t, "testdata/timeout_test.json", "76f0a02f-3b75-4b86-9064-e9195e1b3a02")
+
+	results := engine.StartBatch(context.Background(), assetCache, assetServer, engine.NewDefaultConfig(), test.TestHTTPClient, flow, utils.NewDefaultEnvironment(), batchTestContacts(50), 4)
+
+	sessionsByContact := make(map[flows.ContactUUID]flows.Session)
+	for result := range results {
+		require.NoError(t, result.Err)
+		assert.Equal(t, flows.SessionStatusWaiting, result.Session.Status())
+		assert.Equal(t, result.Contact.UUID(), result.Session.Contact().UUID())
+
+		// each session messages its own contact
+		require.Equal(t, 2, len(result.Events))
+		msgEvent := result.Events[0].(*events.MsgCreatedEvent)
+		assert.Equal(t, fmt.Sprintf("Hi %s! What is your favorite color?", result.Contact.Name()), msgEvent.Msg.Text())
+
+		// and has its own wait which times out when its wait event says it does
+		waitEvent := result.Events[1].(*events.MsgWaitEvent)
+		assert.False(t, flow.Nodes()[0].Wait() == result.Session.Wait())
+		assert.Equal(t, waitEvent.TimeoutOn, result.Session.Wait().TimeoutOn())
+
+		sessionsByContact[result.Contact.UUID()] = result.Session
+	}
+
+	assert.Equal(t, 50, len(sessionsByContact))
+
+	// the flow definition they all share hasn't been modified
+	assert.Nil(t, flow.Nodes()[0].Wait().TimeoutOn())
+
+	// contacts read after the context has been cancelled are returned with an error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	numResults := 0
+	for result := range engine.StartBatch(ctx, assetCache, assetServer, engine.NewDefaultConfig(), test.TestHTTPClient, flow, utils.NewDefaultEnvironment(), batchTestContacts(5), 2) {
+		assert.EqualError(t, result.Err, "context canceled")
+		assert.Nil(t, result.Session)
+		numResults++
+	}
+	assert.Equal(t, 5, numResults)
+}
+
+func benchmarkStartBatch(b *testing.B, file string, flowUUID flows.FlowUUID, numWorkers int) {
+	assetCache, assetServer, flow := batchTestAssets(b, file, flowUUID)
+	config := engine.NewDefaultConfig()
+	env := utils.NewDefaultEnvironment()
+
+	b.ResetTimer()
+
+	for result := range engine.StartBatch(context.Background(), assetCache, assetServer, config, test.TestHTTPClient, flow, env, batchTestContacts(b.N), numWorkers) {
+		if result.Err != nil {
+			b.Fatal(result.Err)
+		}
+	}
+}
+
+// sessions which only use the CPU can only be started faster by more workers if there are more cores to run them on
+func benchmarkStartBatchWithoutIO(b *testing.B, numWorkers int) {
+	benchmarkStartBatch(b, "testdata/timeout_test.json", "76f0a02f-3b75-4b86-9064-e9195e1b3a02", numWorkers)
+}
+
+// sessions which call a slow webhook spend most of their time waiting, so more workers can start more of them at once
+func benchmarkStartBatchWithWebhook(b *testing.B, numWorkers int) {
+	server, err := test.NewTestHTTPServer(49995)
+	require.NoError(b, err)
+	defer server.Close()
+
+	benchmarkStartBatch(b, "testdata/webhook_batch_test.json", "5b8a4f2e-9c3d-4e1f-8a6b-2d7c9e0f1a23", numWorkers)
+}
+
+func BenchmarkStartBatch1Worker(b *testing.B)              { benchmarkStartBatchWithoutIO(b, 1) }
+func BenchmarkStartBatch4Workers(b *testing.B)             { benchmarkStartBatchWithoutIO(b, 4) }
+func BenchmarkStartBatch16Workers(b *testing.B)            { benchmarkStartBatchWithoutIO(b, 16) }
+func BenchmarkStartBatchWithWebhook1Worker(b *testing.B)   { benchmarkStartBatchWithWebhook(b, 1) }
+func BenchmarkStartBatchWithWebhook4Workers(b *testing.B)  { benchmarkStartBatchWithWebhook(b, 4) }
+func BenchmarkStartBatchWithWebhook16Workers(b *testing.B) { benchmarkStartBatchWithWebhook(b, 16) }
@@ -2,39 +2,153 @@ package engine
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
 )
 
 // the configuration options for the flow engine
 type config struct {
-	disableWebhooks         bool
-	webhookMocks            []*flows.WebhookMock
-	maxWebhookResponseBytes int
+	disableWebhooks             bool
+	webhookMocks                []*flows.WebhookMock
+	maxWebhookResponseBytes     int
+	maxStepsPerCall             int
+	maxRunsPerSession           int
+	maxSubflowDepth             int
+	maxNodeVisitsPerCall        int
+	allowLoopsWithExternalCalls bool
+	maxUSSDMsgLength            int
+	webhookRetryBackoff         time.Duration
+	clock                       utils.Clock
 }
 
-// NewConfig returns a new engine configuration
-func NewConfig(disableWebhooks bool, webhookMocks []*flows.WebhookMock, maxWebhookResponseBytes int) flows.EngineConfig {
-	return &config{
-		disableWebhooks:         disableWebhooks,
-		webhookMocks:            webhookMocks,
-		maxWebhookResponseBytes: maxWebhookResponseBytes,
+// NewDefaultConfig returns the default engine configuration
+func NewDefaultConfig() flows.EngineConfig {
+	return NewConfigBuilder().Build()
+}
+
+// ConfigBuilder builds an engine configuration, starting from the defaults and changing only the options which are
+// set on it, e.g. engine.NewConfigBuilder().WithMaxStepsPerCall(50).Build()
+type ConfigBuilder struct {
+	config config
+}
+
+// NewConfigBuilder creates a new builder for an engine configuration
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{
+		config: config{
+			disableWebhooks:             false,
+			webhookMocks:                nil,
+			maxWebhookResponseBytes:     10000,
+			maxStepsPerCall:             100,
+			maxRunsPerSession:           50,
+			maxSubflowDepth:             10,
+			maxNodeVisitsPerCall:        1,
+			allowLoopsWithExternalCalls: false,
+			maxUSSDMsgLength:            182,
+			webhookRetryBackoff:         time.Second,
+			clock:                       nil,
+		},
 	}
 }
 
-// NewDefaultConfig returns the default engine configuration
-func NewDefaultConfig() flows.EngineConfig {
-	return &config{disableWebhooks: false, webhookMocks: nil, maxWebhookResponseBytes: 10000}
+// WithDisableWebhooks sets whether webhooks are mocked rather than called
+func (b *ConfigBuilder) WithDisableWebhooks(disable bool) *ConfigBuilder {
+	b.config.disableWebhooks = disable
+	return b
+}
+
+// WithWebhookMocks sets the mocked responses for webhook calls
+func (b *ConfigBuilder) WithWebhookMocks(mocks []*flows.WebhookMock) *ConfigBuilder {
+	b.config.webhookMocks = mocks
+	return b
+}
+
+// WithMaxWebhookResponseBytes sets the maximum size of webhook response bodies
+func (b *ConfigBuilder) WithMaxWebhookResponseBytes(max int) *ConfigBuilder {
+	b.config.maxWebhookResponseBytes = max
+	return b
+}
+
+// WithMaxStepsPerCall sets the maximum number of steps which can be taken in a single call to the engine
+func (b *ConfigBuilder) WithMaxStepsPerCall(max int) *ConfigBuilder {
+	b.config.maxStepsPerCall = max
+	return b
+}
+
+// WithMaxRunsPerSession sets the maximum number of runs in a session
+func (b *ConfigBuilder) WithMaxRunsPerSession(max int) *ConfigBuilder {
+	b.config.maxRunsPerSession = max
+	return b
+}
+
+// WithMaxSubflowDepth sets the maximum depth of the subflow stack
+func (b *ConfigBuilder) WithMaxSubflowDepth(max int) *ConfigBuilder {
+	b.config.maxSubflowDepth = max
+	return b
+}
+
+// WithMaxNodeVisitsPerCall sets the maximum number of times a node can be visited in a single call to the engine
+func (b *ConfigBuilder) WithMaxNodeVisitsPerCall(max int) *ConfigBuilder {
+	b.config.maxNodeVisitsPerCall = max
+	return b
+}
+
+// WithAllowLoopsWithExternalCalls sets whether loops which call a webhook, resthook or other external service on each
+// iteration are allowed to exceed the node visit limit. Loops with waits never reach the limit as a wait always ends the call.
+func (b *ConfigBuilder) WithAllowLoopsWithExternalCalls(allow bool) *ConfigBuilder {
+	b.config.allowLoopsWithExternalCalls = allow
+	return b
+}
+
+// WithMaxUSSDMsgLength sets the maximum length of messages sent on USSD channels
+func (b *ConfigBuilder) WithMaxUSSDMsgLength(max int) *ConfigBuilder {
+	b.config.maxUSSDMsgLength = max
+	return b
+}
+
+// WithWebhookRetryBackoff sets how long to wait before the first retry of a failed webhook call
+func (b *ConfigBuilder) WithWebhookRetryBackoff(backoff time.Duration) *ConfigBuilder {
+	b.config.webhookRetryBackoff = backoff
+	return b
+}
+
+// WithClock sets the clock used for all timestamps in sessions, which is the only way to control session time
+func (b *ConfigBuilder) WithClock(clock utils.Clock) *ConfigBuilder {
+	b.config.clock = clock
+	return b
+}
+
+// Build returns the engine configuration
+func (b *ConfigBuilder) Build() flows.EngineConfig {
+	config := b.config
+	return &config
 }
 
 func (c *config) DisableWebhooks() bool              { return c.disableWebhooks }
 func (c *config) WebhookMocks() []*flows.WebhookMock { return c.webhookMocks }
 func (c *config) MaxWebhookResponseBytes() int       { return c.maxWebhookResponseBytes }
+func (c *config) MaxStepsPerCall() int               { return c.maxStepsPerCall }
+func (c *config) MaxRunsPerSession() int             { return c.maxRunsPerSession }
+func (c *config) MaxSubflowDepth() int               { return c.maxSubflowDepth }
+func (c *config) MaxNodeVisitsPerCall() int          { return c.maxNodeVisitsPerCall }
+func (c *config) AllowLoopsWithExternalCalls() bool  { return c.allowLoopsWithExternalCalls }
+func (c *config) MaxUSSDMsgLength() int              { return c.maxUSSDMsgLength }
+func (c *config) WebhookRetryBackoff() time.Duration { return c.webhookRetryBackoff }
+func (c *config) Clock() utils.Clock                 { return c.clock }
 
 type configEnvelope struct {
-	DisableWebhooks         *bool                `json:"disable_webhooks"`
-	WebhookMocks            []*flows.WebhookMock `json:"webhook_mocks"`
-	MaxWebhookResponseBytes *int                 `json:"max_webhook_response_bytes"`
+	DisableWebhooks             *bool                `json:"disable_webhooks"`
+	WebhookMocks                []*flows.WebhookMock `json:"webhook_mocks"`
+	MaxWebhookResponseBytes     *int                 `json:"max_webhook_response_bytes"`
+	MaxStepsPerCall             *int                 `json:"max_steps_per_call"`
+	MaxRunsPerSession           *int                 `json:"max_runs_per_session"`
+	MaxSubflowDepth             *int                 `json:"max_subflow_depth"`
+	MaxNodeVisitsPerCall        *int                 `json:"max_node_visits_per_call"`
+	AllowLoopsWithExternalCalls *bool                `json:"allow_loops_with_external_calls"`
+	MaxUSSDMsgLength            *int                 `json:"max_ussd_msg_length"`
+	WebhookRetryBackoffMS       *int                 `json:"webhook_retry_backoff_ms"`
 }
 
 func ReadConfig(data json.RawMessage, base flows.EngineConfig) (flows.EngineConfig, error) {
@@ -43,7 +157,9 @@ func ReadConfig(data json.RawMessage, base flows.EngineConfig) (flows.EngineConf
 		return nil, err
 	}
 
-	config := base.(*config)
+	// copy the base config so that it isn't modified
+	c := *base.(*config)
+	config := &c
 
 	if envelope.DisableWebhooks != nil {
 		config.disableWebhooks = *envelope.DisableWebhooks
@@ -54,6 +170,27 @@ func ReadConfig(data json.RawMessage, base flows.EngineConfig) (flows.EngineConf
 	if envelope.MaxWebhookResponseBytes != nil {
 		config.maxWebhookResponseBytes = *envelope.MaxWebhookResponseBytes
 	}
+	if envelope.MaxStepsPerCall != nil {
+		config.maxStepsPerCall = *envelope.MaxStepsPerCall
+	}
+	if envelope.MaxRunsPerSession != nil {
+		config.maxRunsPerSession = *envelope.MaxRunsPerSession
+	}
+	if envelope.MaxSubflowDepth != nil {
+		config.maxSubflowDepth = *envelope.MaxSubflowDepth
+	}
+	if envelope.MaxNodeVisitsPerCall != nil {
+		config.maxNodeVisitsPerCall = *envelope.MaxNodeVisitsPerCall
+	}
+	if envelope.AllowLoopsWithExternalCalls != nil {
+		config.allowLoopsWithExternalCalls = *envelope.AllowLoopsWithExternalCalls
+	}
+	if envelope.MaxUSSDMsgLength != nil {
+		config.maxUSSDMsgLength = *envelope.MaxUSSDMsgLength
+	}
+	if envelope.WebhookRetryBackoffMS != nil {
+		config.webhookRetryBackoff = time.Duration(*envelope.WebhookRetryBackoffMS) * time.Millisecond
+	}
 
 	return config, nil
 }
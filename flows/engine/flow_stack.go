@@ -5,8 +5,9 @@ import (
 )
 
 type flowFrame struct {
-	flow         flows.Flow
-	visitedNodes map[flows.NodeUUID]bool
+	flow       flows.Flow
+	nodeVisits map[flows.NodeUUID]int
+	visitPath  []flows.NodeUUID
 }
 
 type flowStack struct {
@@ -31,7 +32,7 @@ func flowStackFromRun(run flows.FlowRun) *flowStack {
 
 // creates a new frame for the given flow and pushes it onto the stack
 func (s *flowStack) push(flow flows.Flow) {
-	s.stack = append(s.stack, &flowFrame{flow: flow, visitedNodes: make(map[flows.NodeUUID]bool)})
+	s.stack = append(s.stack, &flowFrame{flow: flow, nodeVisits: make(map[flows.NodeUUID]int)})
 }
 
 // pops the current frame off the stack
@@ -41,14 +42,33 @@ func (s *flowStack) pop() {
 
 // records the given node as visited in the current frame
 func (s *flowStack) visit(nodeUUID flows.NodeUUID) {
-	s.stack[len(s.stack)-1].visitedNodes[nodeUUID] = true
+	frame := s.stack[len(s.stack)-1]
+	frame.nodeVisits[nodeUUID]++
+	frame.visitPath = append(frame.visitPath, nodeUUID)
 }
 
-// checks whether the given node has already been visited in the current frame
-func (s *flowStack) hasVisited(nodeUUID flows.NodeUUID) bool {
-	return s.stack[len(s.stack)-1].visitedNodes[nodeUUID]
+// gets the number of times the given node has been visited in the current frame
+func (s *flowStack) visitCount(nodeUUID flows.NodeUUID) int {
+	return s.stack[len(s.stack)-1].nodeVisits[nodeUUID]
 }
 
+// gets the cycle of nodes in the current frame which re-entering the given node would complete, i.e. the nodes
+// visited since it was last visited, starting and ending with that node
+func (s *flowStack) cycleTo(nodeUUID flows.NodeUUID) []flows.NodeUUID {
+	path := s.stack[len(s.stack)-1].visitPath
+	for p := len(path) - 1; p >= 0; p-- {
+		if path[p] == nodeUUID {
+			cycle := make([]flows.NodeUUID, 0, len(path)-p+1)
+			cycle = append(cycle, path[p:]...)
+			return append(cycle, nodeUUID)
+		}
+	}
+	return nil
+}
+
+// gets the flow of the current frame
+func (s *flowStack) currentFlow() flows.Flow { return s.stack[len(s.stack)-1].flow }
+
 func (s *flowStack) hasFlow(flowUUID flows.FlowUUID) bool {
 	for _, f := range s.stack {
 		if f.flow.UUID() == flowUUID {
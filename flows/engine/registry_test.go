@@ -0,0 +1,193 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/actions"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/flows/waits"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// an action type which isn't part of the engine
+type crmSyncAction struct {
+	actions.BaseAction
+
+	System string `json:"system" validate:"required"`
+}
+
+func (a *crmSyncAction) Type() string                              { return "crm_sync" }
+func (a *crmSyncAction) MakesExternalCall() bool                   { return true }
+func (a *crmSyncAction) Validate(assets flows.SessionAssets) error { return nil }
+func (a *crmSyncAction) Execute(run flows.FlowRun, step flows.Step, log flows.EventLog) error {
+	log.Add(&crmSyncedEvent{BaseEvent: events.NewBaseEvent(), System: a.System})
+	return nil
+}
+
+// an event type generated by that action
+type crmSyncedEvent struct {
+	events.BaseEvent
+	events.EngineOnlyEvent
+
+	System string `json:"system"`
+}
+
+func (e *crmSyncedEvent) Type() string                  { return "crm_synced" }
+func (e *crmSyncedEvent) Apply(run flows.FlowRun) error { return nil }
+
+// a wait type which waits for the CRM to reply
+type crmReplyWait struct{}
+
+func (w *crmReplyWait) Type() string                                   { return "crm_reply" }
+func (w *crmReplyWait) Timeout() *int                                  { return nil }
+func (w *crmReplyWait) TimeoutOn() *time.Time                          { return nil }
+func (w *crmReplyWait) AllowedFlowTypes() []flows.FlowType             { return flows.AllFlowTypes }
+func (w *crmReplyWait) Begin(run flows.FlowRun, step flows.Step) error { return nil }
+func (w *crmReplyWait) CanResume(callerEvents []flows.Event) bool {
+	for _, event := range callerEvents {
+		if event.Type() == "crm_replied" {
+			return true
+		}
+	}
+	return false
+}
+
+// a caller event type which resumes that wait
+type crmRepliedEvent struct {
+	events.BaseEvent
+	events.CallerOnlyEvent
+}
+
+func (e *crmRepliedEvent) Type() string                              { return "crm_replied" }
+func (e *crmRepliedEvent) Validate(assets flows.SessionAssets) error { return nil }
+func (e *crmRepliedEvent) Apply(run flows.FlowRun) error             { return nil }
+
+func init() {
+	actions.RegisterType("crm_sync", func() flows.Action { return &crmSyncAction{} })
+	events.RegisterType("crm_synced", func() flows.Event { return &crmSyncedEvent{} })
+	events.RegisterType("crm_replied", func() flows.Event { return &crmRepliedEvent{} })
+	waits.RegisterType("crm_reply", func() flows.Wait { return &crmReplyWait{} })
+}
+
+var crmAssetsJSON = `[
+	{
+		"type": "flow",
+		"url": "http://testserver/assets/flow/b4f2c1a3-5e6d-4c7b-8a9f-0e1d2c3b4a59",
+		"content": {
+			"uuid": "b4f2c1a3-5e6d-4c7b-8a9f-0e1d2c3b4a59",
+			"name": "CRM Sync",
+			"language": "eng",
+			"nodes": [
+				{
+					"uuid": "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f",
+					"actions": [
+						{
+							"uuid": "2d3e4f5a-6b7c-4d8e-9f0a-1b2c3d4e5f6a",
+							"type": "crm_sync",
+							"system": "Salesforce"
+						}
+					],
+					"wait": {
+						"type": "crm_reply"
+					},
+					"router": {
+						"type": "first"
+					},
+					"exits": [
+						{
+							"uuid": "3e4f5a6b-7c8d-4e9f-8a1b-2c3d4e5f6a7b",
+							"destination_node_uuid": "4f5a6b7c-8d9e-4f0a-9b2c-3d4e5f6a7b8c"
+						}
+					]
+				},
+				{
+					"uuid": "4f5a6b7c-8d9e-4f0a-9b2c-3d4e5f6a7b8c",
+					"actions": [
+						{
+							"uuid": "5a6b7c8d-9e0f-4a1b-8c3d-4e5f6a7b8c9d",
+							"type": "send_msg",
+							"text": "Your details have been synced"
+						}
+					],
+					"exits": [
+						{
+							"uuid": "6b7c8d9e-0f1a-4b2c-9d4e-5f6a7b8c9d0e"
+						}
+					]
+				}
+			]
+		}
+	},
+	{
+		"type": "group_set",
+		"url": "http://testserver/assets/group/",
+		"content": []
+	},
+	{
+		"type": "field_set",
+		"url": "http://testserver/assets/field/",
+		"content": []
+	},
+	{
+		"type": "channel_set",
+		"url": "http://testserver/assets/channel/",
+		"content": []
+	}
+]`
+
+func TestRegisteredTypes(t *testing.T) {
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(crmAssetsJSON)))
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("b4f2c1a3-5e6d-4c7b-8a9f-0e1d2c3b4a59"))
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+	require.NoError(t, session.Start(trigger, nil))
+	assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+	assert.Equal(t, "crm_synced", session.Events()[0].Type())
+	assert.Equal(t, "Salesforce", session.Events()[0].(*crmSyncedEvent).System)
+
+	// the session, including its custom wait and events, survives being serialized
+	sessionJSON, err := json.Marshal(session)
+	require.NoError(t, err)
+
+	session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, sessionJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "crm_reply", session.Wait().Type())
+	assert.Equal(t, "crm_synced", session.Runs()[0].Events()[0].Type())
+
+	// a registered engine-only event can't be sent by callers
+	callerEvents, err := events.ReadEvents([]*utils.TypedEnvelope{{Type: "crm_synced", Data: json.RawMessage(`{"created_on": "2018-01-01T12:00:00Z", "system": "Salesforce"}`)}})
+	require.NoError(t, err)
+	assert.EqualError(t, session.Resume(callerEvents), "event[type=crm_synced] can't be sent by callers")
+
+	// but the caller event which the custom wait is waiting for can be
+	callerEvents, err = events.ReadEvents([]*utils.TypedEnvelope{{Type: "crm_replied", Data: json.RawMessage(`{"created_on": "2018-01-01T12:00:00Z"}`)}})
+	require.NoError(t, err)
+	require.NoError(t, session.Resume(callerEvents))
+
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+	assert.Equal(t, 2, len(session.Runs()[0].Path()))
+
+	// registered action types can declare that they make external calls like the built-in webhook actions
+	assert.True(t, actions.MakesExternalCall(flow.Nodes()[0].Actions()[0]))
+	assert.False(t, actions.MakesExternalCall(flow.Nodes()[1].Actions()[0]))
+	assert.True(t, actions.MakesExternalCall(&actions.CallResthookAction{}))
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// ReplayedCall is the result of replaying a single call (start or resume) to a session
+type ReplayedCall struct {
+	Events []flows.Event
+	Diffs  []*EventDiff
+}
+
+// Matches returns whether this call generated the same events as were recorded for it
+func (c *ReplayedCall) Matches() bool { return len(c.Diffs) == 0 }
+
+// EventDiff describes an event which differs between the recorded log and the replayed call. If the event only exists
+// on one side, the other side is nil.
+type EventDiff struct {
+	Index    int
+	Recorded flows.Event
+	Replayed flows.Event
+}
+
+// Replay rebuilds a session by starting it with the given trigger and then resuming it with each subsequent batch of
+// caller events. The first batch of caller events is passed to the start call. If a recorded log of the events generated
+// by each call is provided, the events generated by each replayed call are compared against it. Timestamps which record
+// when events were created are ignored in those comparisons as they depend on when the original call was made.
+func Replay(assetCache *assets.AssetCache, assetServer assets.AssetServer, engineConfig flows.EngineConfig, httpClient *utils.HTTPClient, trigger flows.Trigger, callerEvents [][]flows.Event, recorded [][]flows.Event) (flows.Session, []*ReplayedCall, error) {
+	if len(callerEvents) == 0 {
+		callerEvents = [][]flows.Event{nil}
+	}
+	if recorded != nil && len(recorded) != len(callerEvents) {
+		return nil, nil, fmt.Errorf("recorded log contains %d calls but there are %d calls to replay", len(recorded), len(callerEvents))
+	}
+
+	session := NewSession(assetCache, assetServer, engineConfig, httpClient)
+	calls := make([]*ReplayedCall, len(callerEvents))
+
+	for c := range callerEvents {
+		if c == 0 {
+			if err := session.Start(trigger, callerEvents[c]); err != nil {
+				return nil, nil, fmt.Errorf("unable to replay start: %s", err)
+			}
+		} else {
+			// sessions are persisted between calls so we do the same to replay them faithfully
+			sessionJSON, err := json.Marshal(session)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to marshal session after call %d: %s", c-1, err)
+			}
+			if session, err = ReadSession(assetCache, assetServer, engineConfig, httpClient, sessionJSON); err != nil {
+				return nil, nil, fmt.Errorf("unable to read session after call %d: %s", c-1, err)
+			}
+			if err := session.Resume(callerEvents[c]); err != nil {
+				return nil, nil, fmt.Errorf("unable to replay resume %d: %s", c, err)
+			}
+		}
+
+		call := &ReplayedCall{Events: session.Events(), Diffs: []*EventDiff{}}
+
+		if recorded != nil {
+			diffs, err := diffEvents(recorded[c], call.Events)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to compare events of call %d: %s", c, err)
+			}
+			call.Diffs = diffs
+		}
+
+		calls[c] = call
+	}
+
+	return session, calls, nil
+}
+
+// compares the given recorded and replayed events by position
+func diffEvents(recorded []flows.Event, replayed []flows.Event) ([]*EventDiff, error) {
+	diffs := make([]*EventDiff, 0)
+
+	for e := 0; e < len(recorded) || e < len(replayed); e++ {
+		diff := &EventDiff{Index: e}
+		if e < len(recorded) {
+			diff.Recorded = recorded[e]
+		}
+		if e < len(replayed) {
+			diff.Replayed = replayed[e]
+		}
+
+		if diff.Recorded != nil && diff.Replayed != nil {
+			recordedJSON, err := normalizedEventJSON(diff.Recorded)
+			if err != nil {
+				return nil, err
+			}
+			replayedJSON, err := normalizedEventJSON(diff.Replayed)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(recordedJSON, replayedJSON) {
+				continue
+			}
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// marshals the given event to JSON with creation timestamps removed and keys sorted
+func normalizedEventJSON(event flows.Event) ([]byte, error) {
+	envelope, err := utils.EnvelopeFromTyped(event)
+	if err != nil {
+		return nil, err
+	}
+	eventJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	var asMap map[string]interface{}
+	if err := json.Unmarshal(eventJSON, &asMap); err != nil {
+		return nil, err
+	}
+
+	removeTimestamps(asMap)
+
+	return json.Marshal(asMap)
+}
+
+// the fields which record when something happened, and so depend on when the original call was made. Other timestamps
+// like timeout_on or expires_on are computed by the engine and so are compared.
+var replayIgnoredTimestamps = map[string]bool{
+	"created_on":  true,
+	"modified_on": true,
+}
+
+// recursively removes the timestamp fields we ignore, e.g. created_on, from the given unmarshalled JSON
+func removeTimestamps(value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typed {
+			if replayIgnoredTimestamps[k] {
+				delete(typed, k)
+			} else {
+				removeTimestamps(v)
+			}
+		}
+	case []interface{}:
+		for _, v := range typed {
+			removeTimestamps(v)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	// engine computed timestamps like timeout_on are compared so the replay needs to use the same clock
+	config := engine.NewConfigBuilder().WithClock(utils.NewFixedClock(time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC))).Build()
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
+	require.NoError(t, err)
+
+	contact, err := flows.ReadContact(session, json.RawMessage(`{"uuid": "5d76d86b-3bb9-4d5a-b822-c9d86f5d8e4f", "name": "Joe", "language": "eng", "urns": ["tel:+18005555777"]}`))
+	require.NoError(t, err)
+
+	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC))
+
+	msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "blue", nil)
+	msgEvent := events.NewMsgReceivedEvent(msg)
+
+	callerEvents := [][]flows.Event{nil, {msgEvent}}
+
+	// record the events generated by starting and resuming a session normally
+	require.NoError(t, session.Start(trigger, nil))
+	startEvents := session.Events()
+
+	sessionJSON, err := json.Marshal(session)
+	require.NoError(t, err)
+	session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, sessionJSON)
+	require.NoError(t, err)
+
+	require.NoError(t, session.Resume(callerEvents[1]))
+	resumeEvents := session.Events()
+
+	recorded := [][]flows.Event{startEvents, resumeEvents}
+
+	// replaying the same trigger and caller events rebuilds the same session
+	replayed, calls, err := engine.Replay(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, trigger, callerEvents, recorded)
+	require.NoError(t, err)
+
+	assert.Equal(t, flows.SessionStatusCompleted, replayed.Status())
+	assert.Equal(t, session.Runs()[0].UUID(), replayed.Runs()[0].UUID())
+	assert.Equal(t, 2, len(calls))
+	assert.True(t, calls[0].Matches())
+	assert.True(t, calls[1].Matches())
+	assert.Equal(t, len(resumeEvents), len(calls[1].Events))
+
+	// if the recorded log differs, we get diffs for the call where it differs
+	recorded[1] = resumeEvents[:len(resumeEvents)-1]
+
+	_, calls, err = engine.Replay(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, trigger, callerEvents, recorded)
+	require.NoError(t, err)
+
+	assert.True(t, calls[0].Matches())
+	assert.False(t, calls[1].Matches())
+	assert.Equal(t, 1, len(calls[1].Diffs))
+	assert.Equal(t, len(resumeEvents)-1, calls[1].Diffs[0].Index)
+	assert.Nil(t, calls[1].Diffs[0].Recorded)
+	assert.Equal(t, events.TypeMsgCreated, calls[1].Diffs[0].Replayed.Type())
+
+	// as does replaying with a different clock, as the wait times out at a different time
+	_, calls, err = engine.Replay(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, trigger, callerEvents, recorded)
+	require.NoError(t, err)
+
+	assert.False(t, calls[0].Matches())
+	assert.Equal(t, events.TypeMsgWait, calls[0].Diffs[0].Replayed.Type())
+
+	// replaying without a recorded log doesn't compare anything
+	_, calls, err = engine.Replay(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, trigger, callerEvents, nil)
+	require.NoError(t, err)
+	assert.True(t, calls[1].Matches())
+
+	// but a recorded log must cover every call
+	_, _, err = engine.Replay(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, trigger, callerEvents, recorded[:1])
+	assert.EqualError(t, err, "recorded log contains 1 calls but there are 2 calls to replay")
+}
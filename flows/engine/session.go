@@ -1,12 +1,18 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/actions"
 	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/classifiers"
 	"github.com/nyaruka/goflow/flows/events"
 	"github.com/nyaruka/goflow/flows/runs"
 	"github.com/nyaruka/goflow/flows/triggers"
@@ -23,7 +29,9 @@ type pushedFlow struct {
 }
 
 type session struct {
-	assets flows.SessionAssets
+	assetCache  *assets.AssetCache
+	assetServer assets.AssetServer
+	assets      flows.SessionAssets
 
 	// state which is maintained between engine calls
 	env     utils.Environment
@@ -32,29 +40,41 @@ type session struct {
 	runs    []flows.FlowRun
 	status  flows.SessionStatus
 	wait    flows.Wait
+	seed    int64
 
 	// state which is temporary to each call
+	ctx        context.Context
 	runsByUUID map[flows.RunUUID]flows.FlowRun
 	pushedFlow *pushedFlow
 	flowStack  *flowStack
 	newEvents  []flows.Event
-
-	engineConfig flows.EngineConfig
-	httpClient   *utils.HTTPClient
+	rand       *rand.Rand
+	uuids      utils.UUIDGenerator
+
+	eventListeners []flows.EventListener
+	engineConfig   flows.EngineConfig
+	httpClient     *utils.HTTPClient
+	classification flows.ClassificationService
+	airtime        flows.AirtimeService
 }
 
 // NewSession creates a new session
 func NewSession(assetCache *assets.AssetCache, assetServer assets.AssetServer, engineConfig flows.EngineConfig, httpClient *utils.HTTPClient) flows.Session {
-	return &session{
-		env:          utils.NewDefaultEnvironment(),
-		assets:       assets.NewSessionAssets(assetCache, assetServer),
-		status:       flows.SessionStatusActive,
-		newEvents:    []flows.Event{},
-		runsByUUID:   make(map[flows.RunUUID]flows.FlowRun),
-		flowStack:    newFlowStack(),
-		engineConfig: engineConfig,
-		httpClient:   httpClient,
-	}
+	s := &session{
+		assetCache:     assetCache,
+		assetServer:    assetServer,
+		env:            utils.NewDefaultEnvironment(),
+		status:         flows.SessionStatusActive,
+		newEvents:      []flows.Event{},
+		runsByUUID:     make(map[flows.RunUUID]flows.FlowRun),
+		flowStack:      newFlowStack(),
+		engineConfig:   engineConfig,
+		httpClient:     httpClient,
+		classification: classifiers.NewKeywordService(),
+	}
+	s.setContext(context.Background())
+	s.seedRandom(s.seed)
+	return s
 }
 
 func (s *session) Assets() flows.SessionAssets          { return s.assets }
@@ -103,6 +123,67 @@ func (s *session) ParentRun() flows.RunSummary {
 	return nil
 }
 
+// Clock returns the clock used for all timestamps in this session, which is the clock from the engine config if there
+// is one. Otherwise it's the current time of the session environment, which is always the system time for environments
+// read from JSON, so a clock can't be set via a trigger.
+func (s *session) Clock() utils.Clock {
+	if s.engineConfig.Clock() != nil {
+		return s.engineConfig.Clock()
+	}
+	return environmentClock{s.env}
+}
+
+// a clock which tells the time of an environment, and waits in real time
+type environmentClock struct {
+	utils.Environment
+}
+
+func (c environmentClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Context returns the context of the current call to this session
+func (s *session) Context() context.Context { return s.ctx }
+
+// sets the context of the current call, which is also used for any asset fetches made during that call
+func (s *session) setContext(ctx context.Context) {
+	s.ctx = ctx
+	s.assets = assets.NewSessionAssets(ctx, s.assetCache, s.assetServer)
+}
+
+// Rand returns the random source of this session
+func (s *session) Rand() *rand.Rand { return s.rand }
+
+// NewUUID generates a new UUID using the UUID generator of this session
+func (s *session) NewUUID() utils.UUID { return s.uuids.Next() }
+
+// seeds our random source and UUID generator from the given seed
+func (s *session) seedRandom(seed int64) {
+	s.rand = utils.NewSeededRand(seed)
+	s.uuids = utils.NewSeededUUID4Generator(s.rand.Int63())
+}
+
+// prepares our random source and UUID generator for a new call, and moves our seed on so that the next call
+// doesn't generate the same values
+func (s *session) beginCall() {
+	s.seedRandom(s.seed)
+	s.seed = s.rand.Int63()
+}
+
+// derives a seed from the given trigger so that sessions started with the same trigger generate the same values
+func seedFromTrigger(trigger flows.Trigger) (int64, error) {
+	envelope, err := utils.EnvelopeFromTyped(trigger)
+	if err != nil {
+		return 0, err
+	}
+	triggerJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := fnv.New64a()
+	hash.Write(triggerJSON)
+	return int64(hash.Sum64()), nil
+}
+
 func (s *session) Status() flows.SessionStatus { return s.status }
 func (s *session) Wait() flows.Wait            { return s.wait }
 
@@ -116,20 +197,50 @@ func (s *session) waitingRun() flows.FlowRun {
 	return nil
 }
 
-func (s *session) LogEvent(event flows.Event) {
-	s.newEvents = append(s.newEvents, event)
+func (s *session) LogEvent(event flows.Event) { s.newEvents = append(s.newEvents, event) }
+func (s *session) Events() []flows.Event      { return s.newEvents }
+
+// AddEventListener adds a listener which will be notified of each event as it is applied in this session
+func (s *session) AddEventListener(listener flows.EventListener) {
+	s.eventListeners = append(s.eventListeners, listener)
 }
-func (s *session) Events() []flows.Event { return s.newEvents }
+
+// EventListeners returns the listeners which are notified of each event applied in this session
+func (s *session) EventListeners() []flows.EventListener { return s.eventListeners }
 
 func (s *session) EngineConfig() flows.EngineConfig { return s.engineConfig }
 func (s *session) HTTPClient() *utils.HTTPClient    { return s.httpClient }
 
+// ClassificationService returns the service used to classify text with NLU classifiers, which by default is the
+// keyword based service
+func (s *session) ClassificationService() flows.ClassificationService { return s.classification }
+
+// SetClassificationService sets the service used to classify text with NLU classifiers
+func (s *session) SetClassificationService(service flows.ClassificationService) {
+	s.classification = service
+}
+
+// AirtimeService returns the service used to transfer airtime, which by default is nil as transfers require a
+// provider configured by the caller
+func (s *session) AirtimeService() flows.AirtimeService { return s.airtime }
+
+// SetAirtimeService sets the service used to transfer airtime
+func (s *session) SetAirtimeService(service flows.AirtimeService) { s.airtime = service }
+
 //------------------------------------------------------------------------------------------
 // Flow execution
 //------------------------------------------------------------------------------------------
 
 // Start beings processing of this session from a trigger and a set of initial caller events
 func (s *session) Start(trigger flows.Trigger, callerEvents []flows.Event) error {
+	return s.StartContext(context.Background(), trigger, callerEvents)
+}
+
+// StartContext is like Start but the given context controls the deadline and cancellation of the call, including any
+// webhook calls and asset fetches made during it
+func (s *session) StartContext(ctx context.Context, trigger flows.Trigger, callerEvents []flows.Event) error {
+	s.setContext(ctx)
+	defer s.setContext(context.Background())
 
 	// check flow is valid and has everything it needs to run
 	if err := trigger.Flow().Validate(s.Assets()); err != nil {
@@ -149,6 +260,15 @@ func (s *session) Start(trigger flows.Trigger, callerEvents []flows.Event) error
 	}
 
 	s.trigger = trigger
+
+	// our seed is derived from our trigger so that the same trigger always produces the same session
+	seed, err := seedFromTrigger(trigger)
+	if err != nil {
+		return fmt.Errorf("unable to derive seed from trigger: %s", err)
+	}
+	s.seed = seed
+	s.beginCall()
+
 	s.PushFlow(trigger.Flow(), nil)
 
 	// off to the races...
@@ -157,10 +277,40 @@ func (s *session) Start(trigger flows.Trigger, callerEvents []flows.Event) error
 
 // Resume tries to resume a waiting session
 func (s *session) Resume(callerEvents []flows.Event) error {
+	return s.ResumeContext(context.Background(), callerEvents)
+}
+
+// ResumeContext is like Resume but the given context controls the deadline and cancellation of the call, including
+// any webhook calls and asset fetches made during it
+func (s *session) ResumeContext(ctx context.Context, callerEvents []flows.Event) error {
+	s.setContext(ctx)
+	defer s.setContext(context.Background())
+
 	if s.status != flows.SessionStatusWaiting {
 		return fmt.Errorf("only waiting sessions can be resumed")
 	}
 
+	// check caller events are valid
+	if err := s.validateCallerEvents(callerEvents); err != nil {
+		return err
+	}
+
+	return s.resume(callerEvents)
+}
+
+// Interrupt interrupts this waiting session, which exits every active or waiting run with a status of interrupted
+// and logs a session_interrupted event
+func (s *session) Interrupt() error {
+	if s.status != flows.SessionStatusWaiting {
+		return fmt.Errorf("only waiting sessions can be interrupted")
+	}
+
+	// this event is generated by the engine rather than sent by our caller, so it's logged like any other engine event
+	return s.resume([]flows.Event{events.NewSessionInterruptedEvent()})
+}
+
+// resumes this waiting session with the given events
+func (s *session) resume(resumeEvents []flows.Event) error {
 	waitingRun := s.waitingRun()
 	if waitingRun == nil {
 		return fmt.Errorf("session doesn't contain any runs which are waiting")
@@ -171,18 +321,18 @@ func (s *session) Resume(callerEvents []flows.Event) error {
 		return fmt.Errorf("validation failed for flow[uuid=%s]: %v", waitingRun.Flow().UUID(), err)
 	}
 
-	// check caller events are valid
-	if err := s.validateCallerEvents(callerEvents); err != nil {
-		return err
-	}
+	s.beginCall()
 
-	if err := s.tryToResume(waitingRun, callerEvents); err != nil {
-		// if we got an error, add it to the log and shut everything down
+	if err := s.tryToResume(waitingRun, resumeEvents); err != nil {
+		// if we got an error, shut everything down
 		for _, run := range s.runs {
 			run.Exit(flows.RunStatusErrored)
 		}
 		s.status = flows.SessionStatusErrored
-		s.LogEvent(events.NewFatalErrorEvent(err))
+
+		// and apply it to the run we were resuming so that it's seen by listeners like any other event
+		step, _, _ := waitingRun.PathLocation()
+		return waitingRun.AddFatalError(step, nil, err)
 	}
 
 	return nil
@@ -208,9 +358,34 @@ func (s *session) tryToResume(waitingRun flows.FlowRun, callerEvents []flows.Eve
 
 	var destination flows.NodeUUID
 
+	// an interruption exits every run so there's nothing left to resume
+	if waitingRun.Status() == flows.RunStatusInterrupted {
+		s.wait = nil
+		s.status = flows.SessionStatusInterrupted
+		return nil
+	}
+
 	// events can change run status so only proceed to the wait if we're still waiting
 	if waitingRun.Status() == flows.RunStatusWaiting {
-		if s.wait.CanResume(callerEvents) {
+		backTo, navigated, err := s.navigateUSSD(waitingRun, step)
+		if err != nil {
+			return err
+		}
+
+		if backTo != noDestination {
+			// the contact navigated back to a previous wait so we leave this step without an exit and go there rather
+			// than routing the input
+			step.Leave("", s.Clock().Now().UTC())
+
+			s.wait = nil
+			s.status = flows.SessionStatusActive
+			waitingRun.SetStatus(flows.RunStatusActive)
+
+			destination = backTo
+		} else if navigated {
+			// the contact is paging through the last message so we're still waiting
+			return nil
+		} else if s.wait.CanResume(callerEvents) {
 			s.wait = nil
 			s.status = flows.SessionStatusActive
 			waitingRun.SetStatus(flows.RunStatusActive)
@@ -225,6 +400,7 @@ func (s *session) tryToResume(waitingRun flows.FlowRun, callerEvents []flows.Eve
 		}
 	}
 
+	s.wait = nil
 	s.status = flows.SessionStatusActive
 
 	// off to the races again...
@@ -249,20 +425,30 @@ func (s *session) findResumeDestination(run flows.FlowRun) (flows.NodeUUID, erro
 
 // the main flow execution loop
 func (s *session) continueUntilWait(currentRun flows.FlowRun, destination flows.NodeUUID, step flows.Step, callerEvents []flows.Event) (err error) {
+	numSteps := 0
+
 	for {
 		// if we have a flow trigger handle that first to find our destination in the new flow
 		if s.pushedFlow != nil {
-			// create a new run for it
-			flow := s.pushedFlow.flow
-			currentRun = runs.NewRun(s, s.pushedFlow.flow, s.contact, currentRun)
-			s.addRun(currentRun)
-			s.flowStack.push(flow)
-
-			// our destination is the first node in that flow... if such a node exists
-			if len(flow.Nodes()) > 0 {
-				destination = flow.Nodes()[0].UUID()
-			} else {
+			if limitEvent := s.checkPushedFlowLimits(); limitEvent != nil {
+				// starting this subflow would exceed our limits so error the parent run instead
+				if err := currentRun.ApplyEvent(step, nil, limitEvent); err != nil {
+					return err
+				}
 				destination = noDestination
+			} else {
+				// create a new run for it
+				flow := s.pushedFlow.flow
+				currentRun = runs.NewRun(s, s.pushedFlow.flow, s.contact, currentRun)
+				s.addRun(currentRun)
+				s.flowStack.push(flow)
+
+				// our destination is the first node in that flow... if such a node exists
+				if len(flow.Nodes()) > 0 {
+					destination = flow.Nodes()[0].UUID()
+				} else {
+					destination = noDestination
+				}
 			}
 
 			// clear the trigger
@@ -281,25 +467,43 @@ func (s *session) continueUntilWait(currentRun flows.FlowRun, destination flows.
 				currentRun = currentRun.ParentInSession()
 				s.flowStack.pop()
 
-				// as long as we didn't error, we can try to resume it
-				if childRun.Status() != flows.RunStatusErrored {
-					if destination, err = s.findResumeDestination(currentRun); err != nil {
-						currentRun.AddFatalError(step, nil, fmt.Errorf("can't resume run as node no longer exists"))
-					}
-				} else {
+				if childRun.Status() == flows.RunStatusErrored {
 					// if we did error then that needs to bubble back up through the run hierarchy
 					step, _, _ := currentRun.PathLocation()
-					currentRun.AddFatalError(step, nil, fmt.Errorf("child run for flow '%s' ended in error, ending execution", childRun.Flow().UUID()))
+					if err := currentRun.AddFatalError(step, nil, fmt.Errorf("child run for flow '%s' ended in error, ending execution", childRun.Flow().UUID())); err != nil {
+						return err
+					}
+				} else if currentRun.ExitedOn() == nil {
+					// as long as the parent hasn't also exited (e.g. expired along with its child), we can try to resume it
+					if childRun.Status() == flows.RunStatusExpired {
+						currentRun.ResetExpiration(nil)
+					}
+
+					if destination, err = s.findResumeDestination(currentRun); err != nil {
+						if err := currentRun.AddFatalError(step, nil, fmt.Errorf("can't resume run as node no longer exists")); err != nil {
+							return err
+						}
+					}
 				}
 
 			} else {
-				// If we have no destination and no parent, then the whole session is done. A run error bubbles up the session status.
+				// If we have no destination and no parent, then the whole session is done. A run error, expiration or
+				// interruption bubbles up the session status.
 				if currentRun.Status() == flows.RunStatusErrored {
 					s.status = flows.SessionStatusErrored
+				} else if currentRun.Status() == flows.RunStatusExpired {
+					s.status = flows.SessionStatusExpired
+				} else if currentRun.Status() == flows.RunStatusInterrupted {
+					s.status = flows.SessionStatusInterrupted
 				} else {
 					s.status = flows.SessionStatusCompleted
 				}
 
+				// let the caller know which message is the last one for any USSD session
+				if err := s.endUSSDSession(currentRun, step); err != nil {
+					return err
+				}
+
 				// return to caller
 				return nil
 			}
@@ -307,11 +511,28 @@ func (s *session) continueUntilWait(currentRun flows.FlowRun, destination flows.
 
 		// if we now have a destination, go there
 		if destination != noDestination {
-			if s.flowStack.hasVisited(destination) {
-				// this is a loop, we log it and stop execution
-				currentRun.AddFatalError(step, nil, fmt.Errorf("flow loop detected, stopping execution before entering '%s'", destination))
+			if err := s.ctx.Err(); err != nil {
+				// our caller has given up on this call, we log it and stop execution
+				if err := currentRun.AddFatalError(step, nil, fmt.Errorf("execution cancelled before entering '%s': %s", destination, err)); err != nil {
+					return err
+				}
+				destination = noDestination
+			} else if numSteps >= s.engineConfig.MaxStepsPerCall() {
+				// we've taken too many steps in this call, we log it and stop execution
+				limitEvent := events.NewExecutionLimitReachedEvent("max_steps_per_call", fmt.Sprintf("maximum of %d steps per call, stopping execution before entering '%s'", s.engineConfig.MaxStepsPerCall(), destination))
+				if err := currentRun.ApplyEvent(step, nil, limitEvent); err != nil {
+					return err
+				}
+				destination = noDestination
+			} else if err := s.checkLoop(destination); err != nil {
+				// this is a loop which isn't allowed, we log it and stop execution
+				if err := currentRun.AddFatalError(step, nil, err); err != nil {
+					return err
+				}
 				destination = noDestination
 			} else {
+				numSteps++
+
 				node := currentRun.Flow().GetNode(destination)
 				if node == nil {
 					return fmt.Errorf("unable to find destination node %s in flow %s", destination, currentRun.Flow().UUID())
@@ -327,7 +548,7 @@ func (s *session) continueUntilWait(currentRun flows.FlowRun, destination flows.
 					return nil
 				}
 
-				// mark this node as visited to prevent loops
+				// record this node as visited so we can detect loops
 				s.flowStack.visit(node.UUID())
 
 				// only pass our caller events to the first node as it is responsible for handling them
@@ -337,6 +558,62 @@ func (s *session) continueUntilWait(currentRun flows.FlowRun, destination flows.
 	}
 }
 
+// checks whether entering the given node would be a loop which isn't allowed by our engine config
+func (s *session) checkLoop(destination flows.NodeUUID) error {
+	if s.flowStack.visitCount(destination) < s.engineConfig.MaxNodeVisitsPerCall() {
+		return nil
+	}
+
+	cycle := s.flowStack.cycleTo(destination)
+
+	// loops which call an external service on each iteration can be allowed as they're not just spinning
+	if s.engineConfig.AllowLoopsWithExternalCalls() && s.cycleMakesExternalCall(cycle) {
+		return nil
+	}
+
+	cycleUUIDs := make([]string, len(cycle))
+	for c := range cycle {
+		cycleUUIDs[c] = string(cycle[c])
+	}
+
+	return fmt.Errorf("flow loop detected, stopping execution before entering '%s' (cycle: %s)", destination, strings.Join(cycleUUIDs, " > "))
+}
+
+// checks whether any node in the given cycle of the current flow has an action which calls an external service
+func (s *session) cycleMakesExternalCall(cycle []flows.NodeUUID) bool {
+	flow := s.flowStack.currentFlow()
+
+	for _, nodeUUID := range cycle {
+		node := flow.GetNode(nodeUUID)
+		if node == nil {
+			continue
+		}
+		for _, action := range node.Actions() {
+			if actions.MakesExternalCall(action) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checks whether starting the pushed flow would exceed the run or subflow depth limits of our engine config, returning
+// an event describing the limit which would be reached if so
+func (s *session) checkPushedFlowLimits() *events.ExecutionLimitReachedEvent {
+	// the first run in a session can't exceed any limits
+	if s.pushedFlow.parentRun == nil {
+		return nil
+	}
+
+	if len(s.runs) >= s.engineConfig.MaxRunsPerSession() {
+		return events.NewExecutionLimitReachedEvent("max_runs_per_session", fmt.Sprintf("maximum of %d runs per session, stopping execution before starting flow: %s", s.engineConfig.MaxRunsPerSession(), s.pushedFlow.flow.UUID()))
+	}
+	if s.flowStack.depth() >= s.engineConfig.MaxSubflowDepth() {
+		return events.NewExecutionLimitReachedEvent("max_subflow_depth", fmt.Sprintf("maximum subflow depth of %d, stopping execution before starting flow: %s", s.engineConfig.MaxSubflowDepth(), s.pushedFlow.flow.UUID()))
+	}
+	return nil
+}
+
 // visits the given node, creating a step in our current run path
 func (s *session) visitNode(run flows.FlowRun, node flows.Node, callerEvents []flows.Event) (flows.Step, flows.NodeUUID, error) {
 	step := run.CreateStep(node)
@@ -383,9 +660,21 @@ func (s *session) visitNode(run flows.FlowRun, node flows.Node, callerEvents []f
 	}
 
 	// if our node has a wait before its router, we hand back to the caller
-	wait := node.Wait()
-	if wait != nil {
-		wait.Begin(run, step)
+	if node.Wait() != nil {
+		// the wait is part of the flow definition which may be shared with other sessions, so we begin our own copy
+		wait, err := waits.CopyWait(node.Wait())
+		if err != nil {
+			return nil, noDestination, err
+		}
+
+		if err := wait.Begin(run, step); err != nil {
+			return nil, noDestination, err
+		}
+
+		// a wait can fail the run if it can't begin, e.g. a delay whose time can't be evaluated
+		if run.Status() == flows.RunStatusErrored {
+			return step, noDestination, nil
+		}
 
 		run.SetStatus(flows.RunStatusWaiting)
 		s.wait = wait
@@ -418,7 +707,7 @@ func (s *session) pickNodeExit(run flows.FlowRun, node flows.Node, step flows.St
 		exitUUID = node.Exits()[0].UUID()
 	}
 
-	step.Leave(exitUUID)
+	step.Leave(exitUUID, s.Clock().Now().UTC())
 
 	// look up our actual exit and localized name
 	var exit flows.Exit
@@ -448,8 +737,10 @@ func (s *session) pickNodeExit(run flows.FlowRun, node flows.Node, step flows.St
 
 	// save our results if appropriate
 	if router != nil && router.ResultName() != "" {
-		event := events.NewRunResultChangedEvent(router.ResultName(), route.Match(), exit.Name(), localizedExitName, node.UUID(), operand)
-		run.ApplyEvent(step, nil, event)
+		event := events.NewRunResultChangedEvent(router.ResultName(), route.Match(), exit.Name(), localizedExitName, node.UUID(), operand, nil)
+		if err := run.ApplyEvent(step, nil, event); err != nil {
+			return nil, noDestination, err
+		}
 	}
 
 	return step, exit.DestinationNodeUUID(), nil
@@ -457,6 +748,8 @@ func (s *session) pickNodeExit(run flows.FlowRun, node flows.Node, step flows.St
 
 const noDestination = flows.NodeUUID("")
 
+// checks that the given caller events are valid and marks them as from the caller so that they keep their own
+// timestamps, stamping any which don't have one with the current time of the session clock
 func (s *session) validateCallerEvents(events []flows.Event) error {
 	for _, event := range events {
 		if event.AllowedOrigin()&flows.EventOriginCaller == 0 {
@@ -465,6 +758,11 @@ func (s *session) validateCallerEvents(events []flows.Event) error {
 		if err := event.Validate(s.assets); err != nil {
 			return fmt.Errorf("validation failed for event[type=%s]: %s", event.Type(), err)
 		}
+
+		event.SetFromCaller(true)
+		if event.CreatedOn().IsZero() {
+			event.SetCreatedOn(s.Clock().Now().UTC())
+		}
 	}
 	return nil
 }
@@ -474,25 +772,34 @@ func (s *session) validateCallerEvents(events []flows.Event) error {
 //------------------------------------------------------------------------------------------
 
 type sessionEnvelope struct {
+	Version     int                  `json:"version"`
 	Environment json.RawMessage      `json:"environment"`
 	Trigger     *utils.TypedEnvelope `json:"trigger"`
 	Contact     *json.RawMessage     `json:"contact,omitempty"`
 	Runs        []json.RawMessage    `json:"runs"`
 	Status      flows.SessionStatus  `json:"status"`
+	Seed        int64                `json:"seed"`
 	Wait        *utils.TypedEnvelope `json:"wait,omitempty"`
 }
 
-// ReadSession decodes a session from the passed in JSON
+// ReadSession decodes a session from the passed in JSON, upgrading it first if it was written by an older version
+// of the engine
 func ReadSession(assetCache *assets.AssetCache, assetServer assets.AssetServer, engineConfig flows.EngineConfig, httpClient *utils.HTTPClient, data json.RawMessage) (flows.Session, error) {
 	var envelope sessionEnvelope
 	var err error
 
+	if data, err = upgradeSession(data); err != nil {
+		return nil, err
+	}
+
 	if err = utils.UnmarshalAndValidate(data, &envelope, "session"); err != nil {
 		return nil, err
 	}
 
 	s := NewSession(assetCache, assetServer, engineConfig, httpClient).(*session)
 	s.status = envelope.Status
+	s.seed = envelope.Seed
+	s.seedRandom(s.seed)
 
 	// read our environment
 	s.env, err = utils.ReadEnvironment(envelope.Environment)
@@ -544,7 +851,9 @@ func (s *session) MarshalJSON() ([]byte, error) {
 	var envelope sessionEnvelope
 	var err error
 
+	envelope.Version = CurrentSessionVersion
 	envelope.Status = s.status
+	envelope.Seed = s.seed
 
 	if envelope.Environment, err = json.Marshal(s.env); err != nil {
 		return nil, err
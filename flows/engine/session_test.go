@@ -1,6 +1,7 @@
 package engine_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,9 +10,13 @@ import (
 
 	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
 	"github.com/nyaruka/goflow/flows/events"
 	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/flows/waits/hints"
 	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,12 +68,15 @@ func TestEvaluateTemplateAsString(t *testing.T) {
 		{"@run.input.created_on", "2000-01-01T00:00:00.000000Z", ""},
 		{"@run.input.channel.name", "My Android Phone", ""},
 		{"@run.status", "completed", ""},
-		{"@run.results", `{"favorite_color":"red","phone_number":"+12344563452"}`, ""},
+		{"@run.results", `{"favorite_color":"red","intent":"greeting","phone_number":"+12344563452"}`, ""},
 		{"@run.results.favorite_color", "red", ""},
 		{"@run.results.favorite_color.category", "Red", ""},
 		{"@run.results.favorite_icecream", "", "error evaluating @run.results.favorite_icecream: no such run result 'favorite_icecream'"},
 		{"@(is_error(run.results.favorite_icecream))", "true", ""},
-		{"@(length(run.results))", "2", ""},
+		{"@(length(run.results))", "3", ""},
+		{"@run.results.intent", "greeting", ""},
+		{"@run.results.intent.category", "Success", ""},
+		{"@run.results.intent.extra.intents.0.confidence", "0.5", ""},
 
 		{"@trigger.params", `{"source": "website","address": {"state": "WA"}}`, ""},
 		{"@trigger.params.source", "website", ""},
@@ -144,7 +152,7 @@ func TestWaitTimeout(t *testing.T) {
 	flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
 	require.NoError(t, err)
 
-	contact := flows.NewContact("Joe", "eng", nil)
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
 	contact.AddURN(urns.URN("tel:+18005555777"))
 	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
 
@@ -190,3 +198,843 @@ func TestWaitTimeout(t *testing.T) {
 	require.Equal(t, "2018-05-04T15:02:30.000000Z", result.Value)
 	require.Nil(t, result.Input)
 }
+
+func TestBusinessHoursTimeout(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/business_hours_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	// 4pm on a Friday for our contact in Los Angeles
+	now := time.Date(2018, 12, 7, 16, 0, 0, 0, la)
+	clock := utils.NewFixedClock(now)
+	config := engine.NewConfigBuilder().WithClock(clock).Build()
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("3e5b2d4a-8c1f-4d6e-9a7b-2f0c1e8d5a64"))
+	require.NoError(t, err)
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	schedule := utils.NewSchedule(weekdays, 9*time.Hour, 17*time.Hour, nil)
+	env := utils.NewEnvironmentWithSchedule(utils.DateFormatYearMonthDay, utils.TimeFormatHourMinute, time.UTC, utils.LanguageList{}, utils.RedactionPolicyNone, schedule)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", la)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	trigger := triggers.NewManualTrigger(env, contact, flow, nil, now)
+
+	require.NoError(t, session.Start(trigger, nil))
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	// the 2 hour timeout only counts the working hours of the contact's timezone so ends at 10am on Monday
+	expectedTimeoutOn := time.Date(2018, 12, 10, 10, 0, 0, 0, la).UTC()
+	assert.Equal(t, expectedTimeoutOn, *session.Wait().TimeoutOn())
+
+	waitEvent := session.Events()[1].(*events.MsgWaitEvent)
+	assert.Equal(t, expectedTimeoutOn, *waitEvent.TimeoutOn)
+
+	// the session can be timed out once Monday morning comes around
+	clock.SetNow(expectedTimeoutOn)
+
+	timeoutEvent := events.NewWaitTimedOutEvent()
+	require.NoError(t, session.Resume([]flows.Event{timeoutEvent}))
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+}
+
+func TestSignalWait(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/signal_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("8c1f3a5e-2b4d-4e6f-9a7b-1c3d5e7f9a0b"))
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+	require.NoError(t, session.Start(trigger, nil))
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	run := session.Runs()[0]
+	require.Equal(t, 2, len(run.Events()))
+	require.Equal(t, events.TypeSignalWait, run.Events()[1].Type())
+
+	waitEvent := run.Events()[1].(*events.SignalWaitEvent)
+	assert.Equal(t, "payment_confirmed", waitEvent.Signal)
+	assert.NotNil(t, waitEvent.TimeoutOn)
+
+	// a signal with a different name doesn't resume the session
+	otherSignal := events.NewSignalReceivedEvent("payment_cancelled", nil)
+
+	require.NoError(t, session.Resume([]flows.Event{otherSignal}))
+	assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+	assert.Equal(t, 1, len(run.Path()))
+	assert.Nil(t, run.Signal())
+
+	// the wait survives being persisted
+	sessionJSON, err := json.Marshal(session)
+	require.NoError(t, err)
+	session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, sessionJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "signal", session.Wait().Type())
+
+	// but the signal we're waiting for does, and its payload can be used by the router and later actions
+	signal := events.NewSignalReceivedEvent("payment_confirmed", json.RawMessage(`{"status": "paid", "amount": 25}`))
+
+	require.NoError(t, session.Resume([]flows.Event{signal}))
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+
+	run = session.Runs()[0]
+	assert.Equal(t, "payment_confirmed", run.Signal().Name)
+	assert.Equal(t, 2, len(run.Path()))
+
+	lastEvent := session.Events()[len(session.Events())-1].(*events.MsgCreatedEvent)
+	assert.Equal(t, "Thanks for your payment of 25", lastEvent.Msg.Text())
+
+	// a later signal wait which times out doesn't see the signal received by an earlier one
+	flow, err = session.Assets().GetFlow(flows.FlowUUID("eefa0534-311a-48e6-a530-abef140f2ad4"))
+	require.NoError(t, err)
+
+	clock := utils.NewFixedClock(time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC))
+	session = engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewConfigBuilder().WithClock(clock).Build(), test.TestHTTPClient)
+	require.NoError(t, session.Start(triggers.NewManualTrigger(nil, contact, flow, nil, time.Now()), nil))
+
+	signal = events.NewSignalReceivedEvent("order_confirmed", json.RawMessage(`{"status": "confirmed"}`))
+	require.NoError(t, session.Resume([]flows.Event{signal}))
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+	assert.Nil(t, session.Runs()[0].Signal())
+
+	clock.Advance(time.Hour * 2)
+	require.NoError(t, session.Resume([]flows.Event{events.NewWaitTimedOutEvent()}))
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+
+	lastEvent = session.Events()[len(session.Events())-1].(*events.MsgCreatedEvent)
+	assert.Equal(t, "Your order is on its way", lastEvent.Msg.Text())
+}
+
+func TestTicketWait(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/ticket_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	readSession := func(data json.RawMessage) flows.Session {
+		session, err := engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, data)
+		require.NoError(t, err)
+		return session
+	}
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("4d2b6f8a-3c1e-4a5b-9d7f-0e2c4a6b8d1f"))
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+	require.NoError(t, session.Start(trigger, nil))
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	run := session.Runs()[0]
+	require.Equal(t, 3, len(run.Events()))
+	require.Equal(t, events.TypeTicketOpened, run.Events()[1].Type())
+	require.Equal(t, events.TypeTicketWait, run.Events()[2].Type())
+
+	openedEvent := run.Events()[1].(*events.TicketOpenedEvent)
+	assert.Equal(t, "Help for Joe", openedEvent.Ticket.Subject)
+	assert.Equal(t, "Contact needs help with their order", openedEvent.Ticket.Body)
+	assert.Equal(t, flows.TicketStatusOpen, openedEvent.Ticket.Status)
+	assert.Equal(t, flows.TicketerUUID("d605bb96-258d-4097-ad0a-080937db2212"), openedEvent.Ticket.Ticketer.UUID)
+
+	ticketUUID := openedEvent.Ticket.UUID
+	assert.Equal(t, ticketUUID, run.Events()[2].(*events.TicketWaitEvent).TicketUUID)
+	assert.Equal(t, ticketUUID, run.Ticket().UUID)
+
+	// the ticket and wait survive being persisted
+	sessionJSON, err := json.Marshal(session)
+	require.NoError(t, err)
+
+	session = readSession(sessionJSON)
+	assert.Equal(t, "ticket", session.Wait().Type())
+	assert.Equal(t, ticketUUID, session.Runs()[0].Ticket().UUID)
+
+	// closing some other ticket errors the session
+	otherClosed := events.NewTicketClosedEvent(flows.TicketUUID("5a3d9c1e-7b2f-4e6a-8d0c-1f3b5d7e9a2c"), "Bob", "Done")
+
+	erroredSession := readSession(sessionJSON)
+	require.NoError(t, erroredSession.Resume([]flows.Event{otherClosed}))
+	assert.Equal(t, flows.SessionStatusErrored, erroredSession.Status())
+
+	errorEvent := erroredSession.Events()[len(erroredSession.Events())-1].(*events.ErrorEvent)
+	assert.Equal(t, "unable to apply event[type=ticket_closed]: run has no ticket with uuid '5a3d9c1e-7b2f-4e6a-8d0c-1f3b5d7e9a2c'", errorEvent.Text)
+
+	// but closing our ticket resumes the session, and its details can be used by the router and later actions
+	closed := events.NewTicketClosedEvent(ticketUUID, "Bob", "Order has been shipped")
+
+	require.NoError(t, session.Resume([]flows.Event{closed}))
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+
+	run = session.Runs()[0]
+	assert.Equal(t, flows.TicketStatusClosed, run.Ticket().Status)
+	assert.Equal(t, 2, len(run.Path()))
+
+	lastEvent := session.Events()[len(session.Events())-1].(*events.MsgCreatedEvent)
+	assert.Equal(t, "Bob closed your ticket: Order has been shipped", lastEvent.Msg.Text())
+
+	// and the opened event still records the ticket as it was opened
+	assert.Equal(t, flows.TicketStatusOpen, run.Events()[1].(*events.TicketOpenedEvent).Ticket.Status)
+}
+
+func TestDelayWait(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/delay_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	now := time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC)
+	clock := utils.NewFixedClock(now)
+	config := engine.NewConfigBuilder().WithClock(clock).Build()
+
+	startSession := func(flowUUID flows.FlowUUID) flows.Session {
+		session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+		flow, err := session.Assets().GetFlow(flowUUID)
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, now)
+
+		require.NoError(t, session.Start(trigger, nil))
+		return session
+	}
+
+	session := startSession(flows.FlowUUID("1e3a5c7e-9b2d-4f4a-8c6e-0a2c4e6a8c0e"))
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	// our wait and the event it generated tell the caller when to resume
+	resumeOn := time.Date(2018, 7, 9, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, resumeOn, *session.Wait().TimeoutOn())
+
+	waitEvent := session.Events()[1].(*events.DelayWaitEvent)
+	assert.Equal(t, resumeOn, waitEvent.TimeoutOn)
+
+	// messages don't resume the session
+	msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "hello?", nil)
+	msgEvent := events.NewMsgReceivedEvent(msg)
+
+	require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+	assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+	assert.Equal(t, 1, len(session.Runs()[0].Path()))
+
+	// but timing out once the delay has passed does
+	clock.SetNow(resumeOn.Add(time.Minute))
+
+	timeoutEvent := events.NewWaitTimedOutEvent()
+
+	require.NoError(t, session.Resume([]flows.Event{timeoutEvent}))
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+	lastEvent := session.Events()[len(session.Events())-1].(*events.MsgCreatedEvent)
+	assert.Equal(t, "Don't forget to complete your profile", lastEvent.Msg.Text())
+
+	// if the expression doesn't evaluate to a time, the run fails
+	clock.SetNow(now)
+	session = startSession(flows.FlowUUID("8f0b2d4f-6c9e-4a1b-9d3f-7b9d1f3b5d7f"))
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+	assert.Equal(t, flows.RunStatusErrored, session.Runs()[0].Status())
+	assert.Nil(t, session.Wait())
+
+	errorEvent := session.Events()[0].(*events.ErrorEvent)
+	assert.True(t, errorEvent.Fatal)
+	assert.Equal(t, "unable to evaluate delay until '@contact.name': unable to convert \"Joe\" to a datetime", errorEvent.Text)
+
+	// and the flow definitions shared by our sessions haven't been modified by their waits
+	for _, flowUUID := range []flows.FlowUUID{"1e3a5c7e-9b2d-4f4a-8c6e-0a2c4e6a8c0e", "8f0b2d4f-6c9e-4a1b-9d3f-7b9d1f3b5d7f"} {
+		flow, err := session.Assets().GetFlow(flowUUID)
+		require.NoError(t, err)
+		assert.Nil(t, flow.Nodes()[0].Wait().TimeoutOn())
+	}
+}
+
+func TestMsgWaitHints(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/hints_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	tests := []struct {
+		input    string
+		exit     string
+		lastText string
+	}{
+		{"1234#", "Valid", "Your PIN is 1234"},
+		{" 9876 ", "Valid", "Your PIN is 9876"},
+		{"12#", "Other", ""},
+		{"abcd#", "Other", ""},
+	}
+
+	for _, tc := range tests {
+		session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("d5a1b3c7-9e2f-4a6b-8c4d-0f1e2a3b4c5d"))
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+		require.NoError(t, session.Start(trigger, nil))
+		require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+		// the hint is included on the wait event so the caller can tell the channel what to expect
+		waitEvent := session.Events()[1].(*events.MsgWaitEvent)
+		waitEventJSON, err := json.Marshal(waitEvent)
+		require.NoError(t, err)
+		assert.Contains(t, string(waitEventJSON), `"hint":{"type":"digits","count":4,"terminated_by":"#"}`)
+
+		// and the hint survives the session being persisted
+		sessionJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, sessionJSON)
+		require.NoError(t, err)
+
+		hint := session.Runs()[0].Events()[1].(*events.MsgWaitEvent).Hint
+		assert.Equal(t, hints.NewDigitsHint(&[]int{4}[0], "#"), hint)
+
+		msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, tc.input, nil)
+		msgEvent := events.NewMsgReceivedEvent(msg)
+
+		require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+		assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+		assert.Equal(t, tc.exit, session.Runs()[0].Results().Get("pin").Category, "exit mismatch for input %s", tc.input)
+
+		if tc.lastText != "" {
+			lastEvent := session.Events()[len(session.Events())-1].(*events.MsgCreatedEvent)
+			assert.Equal(t, tc.lastText, lastEvent.Msg.Text())
+		}
+	}
+}
+
+func TestExecutionLimits(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/limits_test.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		maxSteps  int
+		maxRuns   int
+		maxDepth  int
+		status    flows.SessionStatus
+		numRuns   int
+		limit     string
+		limitText string
+	}{
+		{100, 50, 10, flows.SessionStatusCompleted, 5, "", ""},
+		{6, 50, 10, flows.SessionStatusErrored, 5, "max_steps_per_call", "maximum of 6 steps per call"},
+		{100, 4, 10, flows.SessionStatusErrored, 4, "max_runs_per_session", "maximum of 4 runs per session"},
+		{100, 50, 2, flows.SessionStatusErrored, 2, "max_subflow_depth", "maximum subflow depth of 2"},
+	}
+
+	for _, tc := range tests {
+		assetCache := assets.NewAssetCache(100, 5)
+		require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+		config := engine.NewConfigBuilder().WithMaxStepsPerCall(tc.maxSteps).WithMaxRunsPerSession(tc.maxRuns).WithMaxSubflowDepth(tc.maxDepth).Build()
+		session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("615b8a0f-588c-4d20-a05f-363b0b4ce6f4"))
+		require.NoError(t, err)
+
+		trigger := triggers.NewManualTrigger(nil, flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil), flow, nil, time.Now())
+
+		err = session.Start(trigger, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.status, session.Status(), "session status mismatch for limits %d/%d/%d", tc.maxSteps, tc.maxRuns, tc.maxDepth)
+		assert.Equal(t, tc.numRuns, len(session.Runs()), "run count mismatch for limits %d/%d/%d", tc.maxSteps, tc.maxRuns, tc.maxDepth)
+
+		var limitEvent *events.ExecutionLimitReachedEvent
+		for _, event := range session.Events() {
+			if event.Type() == events.TypeExecutionLimitReached {
+				limitEvent = event.(*events.ExecutionLimitReachedEvent)
+				break
+			}
+		}
+
+		if tc.limit != "" {
+			require.NotNil(t, limitEvent, "expected limit event for limits %d/%d/%d", tc.maxSteps, tc.maxRuns, tc.maxDepth)
+			assert.Equal(t, tc.limit, limitEvent.Limit)
+			assert.Contains(t, limitEvent.Text, tc.limitText)
+		} else {
+			assert.Nil(t, limitEvent)
+		}
+	}
+}
+
+func TestLoopPolicy(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/loops_test.json")
+	require.NoError(t, err)
+
+	counterFlow := flows.FlowUUID("b1b7d8b2-6d0e-4a51-9a4e-3c5e2f1d7a01")
+	retryFlow := flows.FlowUUID("c2c8e9c3-7e1f-4b62-8b5f-4d6f3a2e8b02")
+	resthookFlow := flows.FlowUUID("d3d9f0d4-8f2a-4c73-9c6a-5e7a4b3f9c03")
+
+	tests := []struct {
+		flowUUID                    flows.FlowUUID
+		maxNodeVisits               int
+		allowLoopsWithExternalCalls bool
+		status                      flows.SessionStatus
+		error                       string
+	}{
+		{counterFlow, 1, false, flows.SessionStatusErrored, "flow loop detected, stopping execution before entering 'a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21' (cycle: a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21 > a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21)"},
+		{counterFlow, 2, false, flows.SessionStatusErrored, "flow loop detected, stopping execution before entering 'a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21' (cycle: a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21 > a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21)"},
+		{counterFlow, 3, false, flows.SessionStatusCompleted, ""},
+		{counterFlow, 1, true, flows.SessionStatusErrored, "flow loop detected, stopping execution before entering 'a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21' (cycle: a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21 > a1a6c3b0-2f4e-4c8d-9e1a-5b7c9d0e1f21)"},
+		{retryFlow, 1, false, flows.SessionStatusErrored, "flow loop detected, stopping execution before entering 'b2b7d4c1-3a5f-4d9e-8f2b-6c8d0e1f2a32' (cycle: b2b7d4c1-3a5f-4d9e-8f2b-6c8d0e1f2a32 > c3c8e5d2-4b6a-4e0f-9a3c-7d9e1f2a3b43 > b2b7d4c1-3a5f-4d9e-8f2b-6c8d0e1f2a32)"},
+		{retryFlow, 1, true, flows.SessionStatusCompleted, ""},
+		{resthookFlow, 1, false, flows.SessionStatusErrored, "flow loop detected, stopping execution before entering 'e4e0a1e5-9a3b-4d84-8d7b-6f8b5c4a0d14' (cycle: e4e0a1e5-9a3b-4d84-8d7b-6f8b5c4a0d14 > e4e0a1e5-9a3b-4d84-8d7b-6f8b5c4a0d14)"},
+		{resthookFlow, 1, true, flows.SessionStatusCompleted, ""},
+	}
+
+	for _, tc := range tests {
+		assetCache := assets.NewAssetCache(100, 5)
+		require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+		config := engine.NewConfigBuilder().WithDisableWebhooks(true).WithMaxNodeVisitsPerCall(tc.maxNodeVisits).WithAllowLoopsWithExternalCalls(tc.allowLoopsWithExternalCalls).Build()
+		session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+		flow, err := session.Assets().GetFlow(tc.flowUUID)
+		require.NoError(t, err)
+
+		trigger := triggers.NewManualTrigger(nil, flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil), flow, nil, time.Now())
+
+		require.NoError(t, session.Start(trigger, nil))
+		assert.Equal(t, tc.status, session.Status(), "session status mismatch for %s with policy %d/%v", flow.Name(), tc.maxNodeVisits, tc.allowLoopsWithExternalCalls)
+
+		var fatalError string
+		for _, event := range session.Events() {
+			if event.Type() == events.TypeError && event.(*events.ErrorEvent).Fatal {
+				fatalError = event.(*events.ErrorEvent).Text
+			}
+		}
+		assert.Equal(t, tc.error, fatalError, "error mismatch for %s with policy %d/%v", flow.Name(), tc.maxNodeVisits, tc.allowLoopsWithExternalCalls)
+
+		// completed loops ran until their counters reached 3
+		if tc.status == flows.SessionStatusCompleted {
+			require.Equal(t, 1, len(session.Runs()[0].Results()))
+			for _, result := range session.Runs()[0].Results() {
+				assert.Equal(t, "3", result.Value)
+			}
+		}
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/subflow_test.json")
+	require.NoError(t, err)
+
+	startSession := func() flows.Session {
+		session, err := test.CreateSession(json.RawMessage(sessionAssets))
+		require.NoError(t, err)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("9b7b0f45-3d1a-4c8e-b3a5-7f6e2d1c0b9a"))
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+		require.NoError(t, session.Start(trigger, nil))
+		require.Equal(t, flows.SessionStatusWaiting, session.Status())
+		require.Equal(t, 2, len(session.Runs()))
+		return session
+	}
+
+	// interrupting a waiting session exits every run, without resuming the parent of the waiting run
+	session := startSession()
+	numEvents := len(session.Events())
+	require.NoError(t, session.Interrupt())
+
+	parent, child := session.Runs()[0], session.Runs()[1]
+	assert.Equal(t, flows.RunStatusInterrupted, child.Status())
+	assert.NotNil(t, child.ExitedOn())
+	assert.Equal(t, flows.RunStatusInterrupted, parent.Status())
+	assert.NotNil(t, parent.ExitedOn())
+	assert.Nil(t, parent.Results().Get("child_status"))
+	assert.Equal(t, flows.SessionStatusInterrupted, session.Status())
+	assert.Nil(t, session.Wait())
+
+	// and the interruption is the only event logged
+	require.Equal(t, numEvents+1, len(session.Events()))
+	assert.Equal(t, events.TypeSessionInterrupted, session.Events()[numEvents].Type())
+	assert.False(t, session.Events()[numEvents].CreatedOn().IsZero())
+
+	// can't interrupt or resume a session which is no longer waiting
+	assert.EqualError(t, session.Interrupt(), "only waiting sessions can be interrupted")
+	assert.EqualError(t, session.Resume(nil), "only waiting sessions can be resumed")
+
+	// a caller can also interrupt a waiting session with a session_interrupted event
+	session = startSession()
+	require.NoError(t, session.Resume([]flows.Event{events.NewSessionInterruptedEvent()}))
+
+	assert.Equal(t, flows.RunStatusInterrupted, session.Runs()[1].Status())
+	assert.Equal(t, flows.RunStatusInterrupted, session.Runs()[0].Status())
+	assert.Equal(t, flows.SessionStatusInterrupted, session.Status())
+
+	// interrupting a session whose waiting run has no parent interrupts the session itself
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("e4c3b2a1-9f8e-4d7c-8b6a-5f4e3d2c1b0a"))
+	require.NoError(t, err)
+
+	session, err = test.CreateSession(json.RawMessage(sessionAssets))
+	require.NoError(t, err)
+	require.NoError(t, session.Start(triggers.NewManualTrigger(nil, flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil), flow, nil, time.Now()), nil))
+	require.NoError(t, session.Interrupt())
+
+	assert.Equal(t, flows.RunStatusInterrupted, session.Runs()[0].Status())
+	assert.Equal(t, flows.SessionStatusInterrupted, session.Status())
+}
+
+func TestRunExpiration(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/subflow_test.json")
+	require.NoError(t, err)
+
+	startSession := func() (flows.Session, flows.FlowRun, flows.FlowRun) {
+		session, err := test.CreateSession(json.RawMessage(sessionAssets))
+		require.NoError(t, err)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("9b7b0f45-3d1a-4c8e-b3a5-7f6e2d1c0b9a"))
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+		require.NoError(t, session.Start(trigger, nil))
+		require.Equal(t, flows.SessionStatusWaiting, session.Status())
+		require.Equal(t, 2, len(session.Runs()))
+
+		parent, child := session.Runs()[0], session.Runs()[1]
+		require.NotNil(t, parent.ExpiresOn())
+		require.NotNil(t, child.ExpiresOn())
+		require.True(t, parent.ExpiresOn().After(*child.ExpiresOn()))
+
+		return session, parent, child
+	}
+
+	// expiring the waiting child run resumes the parent which can route on how the child ended
+	session, parent, child := startSession()
+	session.Environment().(*test.TestEnvironment).SetNow(child.ExpiresOn().Add(time.Second))
+
+	require.NoError(t, session.Resume([]flows.Event{&events.RunExpiredEvent{RunUUID: child.UUID()}}))
+
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+	assert.Nil(t, session.Wait())
+	assert.Equal(t, flows.RunStatusExpired, child.Status())
+	assert.Equal(t, flows.RunStatusCompleted, parent.Status())
+	assert.Equal(t, 2, len(parent.Path()))
+
+	msgEvent := parent.Events()[len(parent.Events())-1].(*events.MsgCreatedEvent)
+	assert.Equal(t, "Child run ended with status expired", msgEvent.Msg.Text())
+
+	// expiring the root run expires the entire session
+	session, parent, child = startSession()
+	session.Environment().(*test.TestEnvironment).SetNow(parent.ExpiresOn().Add(time.Second))
+
+	require.NoError(t, session.Resume([]flows.Event{&events.RunExpiredEvent{RunUUID: parent.UUID()}}))
+
+	assert.Equal(t, flows.SessionStatusExpired, session.Status())
+	assert.Nil(t, session.Wait())
+	assert.Equal(t, flows.RunStatusExpired, child.Status())
+	assert.Equal(t, flows.RunStatusExpired, parent.Status())
+	assert.NotNil(t, parent.ExitedOn())
+	assert.Equal(t, 1, len(parent.Path()))
+
+	// a run can't be expired before its expiration time
+	session, _, child = startSession()
+	session.Environment().(*test.TestEnvironment).SetNow(child.ExpiresOn().Add(-time.Second))
+
+	require.NoError(t, session.Resume([]flows.Event{&events.RunExpiredEvent{RunUUID: child.UUID()}}))
+
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+	assert.Equal(t, "unable to apply event[type=run_expired]: can't expire run before its expiration", session.Events()[len(session.Events())-1].(*events.ErrorEvent).Text)
+
+	// and only the waiting run or its parents can be expired
+	session, _, _ = startSession()
+
+	require.NoError(t, session.Resume([]flows.Event{&events.RunExpiredEvent{RunUUID: flows.RunUUID("0e06f977-cbb7-475f-9d0b-a0c4aaec7f6a")}}))
+
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+	assert.Equal(t, "unable to apply event[type=run_expired]: only the waiting run or one of its parents can be expired", session.Events()[len(session.Events())-1].(*events.ErrorEvent).Text)
+}
+
+func TestConfigClock(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	now := time.Date(2018, 7, 6, 12, 30, 0, 123456789, time.UTC)
+	clock := utils.NewFixedClock(now)
+	config := engine.NewConfigBuilder().WithClock(clock).Build()
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	trigger := triggers.NewManualTrigger(nil, contact, flow, nil, now)
+
+	require.NoError(t, session.Start(trigger, nil))
+
+	// all timestamps and timeouts come from our clock
+	run := session.Runs()[0]
+	assert.Equal(t, now, run.CreatedOn())
+	assert.Equal(t, now, run.Path()[0].ArrivedOn())
+	assert.Equal(t, now, run.Environment().Now().UTC())
+	assert.Equal(t, now.Add(time.Minute*10), *session.Wait().TimeoutOn())
+	for _, event := range session.Events() {
+		assert.Equal(t, now, event.CreatedOn())
+	}
+
+	// move our clock past the timeout so that we can resume
+	clock.Advance(time.Minute * 11)
+
+	timeoutEvent := events.NewWaitTimedOutEvent()
+	timeoutEvent.CreatedOn_ = clock.Now()
+
+	require.NoError(t, session.Resume([]flows.Event{timeoutEvent}))
+
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+	assert.Equal(t, now.Add(time.Minute*11), *run.Path()[0].LeftOn())
+	assert.Equal(t, now.Add(time.Minute*11), *run.ExitedOn())
+}
+
+func TestSessionSeed(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	now := time.Date(2018, 7, 6, 12, 30, 0, 123456789, time.UTC)
+	config := engine.NewConfigBuilder().WithClock(utils.NewFixedClock(now)).Build()
+
+	runSession := func() (json.RawMessage, json.RawMessage) {
+		session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
+		require.NoError(t, err)
+
+		contact, err := flows.ReadContact(session, json.RawMessage(`{"uuid": "5d76d86b-3bb9-4d5a-b822-c9d86f5d8e4f", "name": "Joe", "language": "eng", "urns": ["tel:+18005555777"]}`))
+		require.NoError(t, err)
+
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, now)
+
+		require.NoError(t, session.Start(trigger, nil))
+
+		startJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+
+		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, startJSON)
+		require.NoError(t, err)
+
+		msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "red", nil)
+		msgEvent := events.NewMsgReceivedEvent(msg)
+		msgEvent.CreatedOn_ = now
+
+		require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+
+		resumeJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+
+		return startJSON, resumeJSON
+	}
+
+	// the same trigger and caller events produce identical sessions
+	start1, resume1 := runSession()
+	start2, resume2 := runSession()
+
+	assert.Equal(t, string(start1), string(start2))
+	assert.Equal(t, string(resume1), string(resume2))
+
+	// but each call moves the seed on so UUIDs aren't reused within a session
+	var startEnvelope, resumeEnvelope struct {
+		Seed int64 `json:"seed"`
+		Runs []struct {
+			Path []struct {
+				UUID string `json:"uuid"`
+			} `json:"path"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(start1, &startEnvelope))
+	require.NoError(t, json.Unmarshal(resume1, &resumeEnvelope))
+
+	assert.NotEqual(t, startEnvelope.Seed, resumeEnvelope.Seed)
+	assert.Equal(t, 2, len(resumeEnvelope.Runs[0].Path))
+	assert.NotEqual(t, resumeEnvelope.Runs[0].Path[0].UUID, resumeEnvelope.Runs[0].Path[1].UUID)
+	assert.NotEqual(t, startEnvelope.Runs[0].Path[0].UUID, resumeEnvelope.Runs[0].Path[1].UUID)
+}
+
+func TestEventListeners(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	startSession := func(listener flows.EventListener) (flows.Session, error) {
+		session, err := test.CreateSession(json.RawMessage(sessionAssets))
+		require.NoError(t, err)
+
+		session.AddEventListener(listener)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+		return session, session.Start(trigger, nil)
+	}
+
+	// listeners see each event as it's applied along with the action and step that produced it
+	var seen []string
+	session, err := startSession(flows.EventListenerFunc(func(run flows.FlowRun, step flows.Step, action flows.Action, event flows.Event) error {
+		actionUUID := ""
+		if action != nil {
+			actionUUID = string(action.UUID())
+		}
+		assert.Equal(t, step.UUID(), event.StepUUID())
+		seen = append(seen, fmt.Sprintf("%s:%s", event.Type(), actionUUID))
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"msg_created:e97cd6d5-3354-4dbd-85bc-6c1f87849eec", "msg_wait:"}, seen)
+	assert.Equal(t, 2, len(session.Events()))
+
+	msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "red", nil)
+	msgEvent := events.NewMsgReceivedEvent(msg)
+
+	require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+
+	assert.Equal(t, []string{"msg_created:e97cd6d5-3354-4dbd-85bc-6c1f87849eec", "msg_wait:", "msg_received:", "run_result_changed:", "msg_created:d2a4052a-3fa9-4608-ab3e-5b9631440447"}, seen)
+
+	// a listener can veto an event which aborts execution
+	vetoEvents := func(eventType string) flows.EventListener {
+		return flows.EventListenerFunc(func(run flows.FlowRun, step flows.Step, action flows.Action, event flows.Event) error {
+			if event.Type() == eventType {
+				return fmt.Errorf("%s events are disabled", eventType)
+			}
+			return nil
+		})
+	}
+
+	session, err = startSession(vetoEvents(events.TypeMsgCreated))
+	assert.EqualError(t, err, "event[type=msg_created] vetoed by listener: msg_created events are disabled")
+	assert.Equal(t, 0, len(session.Events()))
+
+	// including events applied by waits
+	session, err = startSession(vetoEvents(events.TypeMsgWait))
+	assert.EqualError(t, err, "event[type=msg_wait] vetoed by listener: msg_wait events are disabled")
+	assert.Nil(t, session.Wait())
+
+	// and events applied when routing, which when resuming errors the session
+	session, err = startSession(vetoEvents(events.TypeRunResultChanged))
+	require.NoError(t, err)
+
+	var seenErrors []*events.ErrorEvent
+	session.AddEventListener(flows.EventListenerFunc(func(run flows.FlowRun, step flows.Step, action flows.Action, event flows.Event) error {
+		if errorEvent, isError := event.(*events.ErrorEvent); isError {
+			seenErrors = append(seenErrors, errorEvent)
+		}
+		return nil
+	}))
+
+	require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+	assert.Equal(t, flows.RunStatusErrored, session.Runs()[0].Status())
+	assert.Nil(t, session.Runs()[0].Results().Get("favorite_color"))
+
+	lastEvent := session.Events()[len(session.Events())-1].(*events.ErrorEvent)
+	assert.True(t, lastEvent.Fatal)
+	assert.Equal(t, "event[type=run_result_changed] vetoed by listener: run_result_changed events are disabled", lastEvent.Text)
+
+	// the fatal error is also seen by listeners
+	assert.Equal(t, []*events.ErrorEvent{lastEvent}, seenErrors)
+	assert.False(t, lastEvent.CreatedOn().IsZero())
+}
+
+func TestCancellation(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	findFatalError := func(session flows.Session) *events.ErrorEvent {
+		for _, event := range session.Events() {
+			if event.Type() == events.TypeError && event.(*events.ErrorEvent).Fatal {
+				return event.(*events.ErrorEvent)
+			}
+		}
+		return nil
+	}
+
+	startSession := func(ctx context.Context) flows.Session {
+		session, err := test.CreateSession(json.RawMessage(sessionAssets))
+		require.NoError(t, err)
+
+		flow, err := session.Assets().GetFlow(flows.FlowUUID("76f0a02f-3b75-4b86-9064-e9195e1b3a02"))
+		require.NoError(t, err)
+
+		contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+		contact.AddURN(urns.URN("tel:+18005555777"))
+		trigger := triggers.NewManualTrigger(nil, contact, flow, nil, time.Now())
+
+		require.NoError(t, session.StartContext(ctx, trigger, nil))
+		return session
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// starting with a context which has already been cancelled errors the session before it enters any nodes
+	session := startSession(cancelled)
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+	assert.Equal(t, flows.RunStatusErrored, session.Runs()[0].Status())
+
+	errorEvent := findFatalError(session)
+	require.NotNil(t, errorEvent)
+	assert.Contains(t, errorEvent.Text, "execution cancelled before entering")
+	assert.Contains(t, errorEvent.Text, "context canceled")
+
+	// once the call is over, the session goes back to using a background context
+	assert.NoError(t, session.Context().Err())
+
+	// a resume whose deadline has passed stops execution once the waiting node has handled the caller events
+	session = startSession(context.Background())
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "red", nil)
+	msgEvent := events.NewMsgReceivedEvent(msg)
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	require.NoError(t, session.ResumeContext(expired, []flows.Event{msgEvent}))
+	assert.Equal(t, flows.SessionStatusErrored, session.Status())
+
+	errorEvent = findFatalError(session)
+	require.NotNil(t, errorEvent)
+	assert.Contains(t, errorEvent.Text, "context deadline exceeded")
+}
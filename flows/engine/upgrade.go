@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+)
+
+// CurrentSessionVersion is the version of the session JSON written by this engine. It should be incremented whenever
+// a change is made to how sessions are serialized, and an upgrade registered from the previous version.
+const CurrentSessionVersion = 2
+
+// sessions written before versioning was introduced don't have a version field and are treated as this version
+const unversionedSessionVersion = 1
+
+// SessionUpgrade is a function which takes the JSON of a session of one version and returns the JSON of that session
+// in the next version
+type SessionUpgrade func(json.RawMessage) (json.RawMessage, error)
+
+var sessionUpgrades = map[int]SessionUpgrade{}
+
+// RegisterSessionUpgrade registers an upgrade to be applied to sessions of the given version to bring them to the
+// next version
+func RegisterSessionUpgrade(fromVersion int, upgrade SessionUpgrade) {
+	sessionUpgrades[fromVersion] = upgrade
+}
+
+func init() {
+	RegisterSessionUpgrade(1, upgradeSessionToV2)
+}
+
+// reads the version of the given session JSON
+func readSessionVersion(data json.RawMessage) (int, error) {
+	versioned := &struct {
+		Version int `json:"version"`
+	}{}
+	if err := json.Unmarshal(data, versioned); err != nil {
+		return 0, err
+	}
+	if versioned.Version == 0 {
+		return unversionedSessionVersion, nil
+	}
+	return versioned.Version, nil
+}
+
+// upgrades the given session JSON to the current version by applying each registered upgrade in turn
+func upgradeSession(data json.RawMessage) (json.RawMessage, error) {
+	version, err := readSessionVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentSessionVersion {
+		return nil, fmt.Errorf("session version %d is newer than the current version %d", version, CurrentSessionVersion)
+	}
+
+	for ; version < CurrentSessionVersion; version++ {
+		upgrade, found := sessionUpgrades[version]
+		if !found {
+			return nil, fmt.Errorf("no upgrade registered for sessions of version %d", version)
+		}
+		if data, err = upgrade(data); err != nil {
+			return nil, fmt.Errorf("unable to upgrade session from version %d: %s", version, err)
+		}
+	}
+
+	return data, nil
+}
+
+// version 2 sessions carry a seed for their random source and UUID generator. Older sessions are given one derived from
+// their trigger so that each one still gets its own sequence of values.
+func upgradeSessionToV2(data json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	if _, hasSeed := fields["seed"]; !hasSeed {
+		hash := fnv.New64a()
+		hash.Write(fields["trigger"])
+
+		seedJSON, err := json.Marshal(int64(hash.Sum64()))
+		if err != nil {
+			return nil, err
+		}
+		fields["seed"] = seedJSON
+	}
+
+	fields["version"] = json.RawMessage(`2`)
+
+	return json.Marshal(fields)
+}
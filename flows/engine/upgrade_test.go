@@ -0,0 +1,71 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionUpgrades(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	readSession := func(data json.RawMessage) (flows.Session, error) {
+		return engine.ReadSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), test.TestHTTPClient, data)
+	}
+
+	// every previous version of the session format should be readable and resumable
+	for version := 1; version <= engine.CurrentSessionVersion; version++ {
+		sessionJSON, err := ioutil.ReadFile(fmt.Sprintf("testdata/sessions/v%d.json", version))
+		require.NoError(t, err)
+
+		session, err := readSession(sessionJSON)
+		require.NoError(t, err, "unable to read session of version %d", version)
+		assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+		upgradedJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+
+		upgraded := &struct {
+			Version int   `json:"version"`
+			Seed    int64 `json:"seed"`
+		}{}
+		require.NoError(t, json.Unmarshal(upgradedJSON, upgraded))
+		assert.Equal(t, engine.CurrentSessionVersion, upgraded.Version, "version mismatch for session of version %d", version)
+		assert.NotEqual(t, int64(0), upgraded.Seed, "seed missing for session of version %d", version)
+
+		msg := flows.NewMsgIn(flows.MsgUUID("9bf91c2b-ce58-4cef-aacc-281e03f69ab5"), urns.URN("tel:+18005555777"), nil, "red", nil)
+		msgEvent := events.NewMsgReceivedEvent(msg)
+
+		require.NoError(t, session.Resume([]flows.Event{msgEvent}))
+		assert.Equal(t, flows.SessionStatusCompleted, session.Status(), "resume failed for session of version %d", version)
+	}
+
+	// upgrading is deterministic so an unversioned session always gets the same seed
+	v1JSON, err := ioutil.ReadFile("testdata/sessions/v1.json")
+	require.NoError(t, err)
+
+	session1, err := readSession(v1JSON)
+	require.NoError(t, err)
+	session2, err := readSession(v1JSON)
+	require.NoError(t, err)
+	assert.Equal(t, session1.NewUUID(), session2.NewUUID())
+
+	// sessions from the future can't be read
+	_, err = readSession(json.RawMessage(fmt.Sprintf(`{"version": %d}`, engine.CurrentSessionVersion+1)))
+	assert.EqualError(t, err, fmt.Sprintf("session version %d is newer than the current version %d", engine.CurrentSessionVersion+1, engine.CurrentSessionVersion))
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/inputs"
+	"github.com/nyaruka/goflow/flows/waits"
+)
+
+// handles the USSD navigation commands whilst the given run is waiting at a USSD wait. If the contact asked for the
+// next or previous page of the last message we send it and the run keeps waiting. If they went back from the first
+// page then we return the node of the previous wait in the run so that it can be re-entered.
+func (s *session) navigateUSSD(run flows.FlowRun, step flows.Step) (flows.NodeUUID, bool, error) {
+	if _, isUSSD := s.wait.(*waits.USSDWait); !isUSSD {
+		return noDestination, false, nil
+	}
+
+	input, isMsg := run.Input().(*inputs.MsgInput)
+	if !isMsg {
+		return noDestination, false, nil
+	}
+
+	command := strings.TrimSpace(input.Text())
+	if command != flows.USSDNext && command != flows.USSDBack {
+		return noDestination, false, nil
+	}
+
+	// find the last message sent before we started waiting
+	var lastMsg *events.MsgCreatedEvent
+	runEvents := run.Events()
+	for e := len(runEvents) - 1; e >= 0 && lastMsg == nil; e-- {
+		if runEvents[e].StepUUID() == step.UUID() {
+			lastMsg, _ = runEvents[e].(*events.MsgCreatedEvent)
+		}
+	}
+
+	if lastMsg != nil && len(lastMsg.Pages) > 0 {
+		page := lastMsg.Page + 1
+		if command == flows.USSDBack {
+			page = lastMsg.Page - 1
+		}
+
+		if page >= 1 && page <= len(lastMsg.Pages) {
+			msg := lastMsg.Msg
+			msg.UUID_ = flows.MsgUUID(s.NewUUID())
+			msg.Text_ = lastMsg.Pages[page-1]
+
+			if err := run.ApplyEvent(step, nil, events.NewPagedMsgCreatedEvent(&msg, page, lastMsg.Pages)); err != nil {
+				return noDestination, false, err
+			}
+			return noDestination, true, nil
+		}
+	}
+
+	// going back from the first page takes us back to the previous wait
+	if command == flows.USSDBack {
+		path := run.Path()
+		for p := len(path) - 2; p >= 0; p-- {
+			node := run.Flow().GetNode(path[p].NodeUUID())
+			if node != nil && node.Wait() != nil {
+				return node.UUID(), true, nil
+			}
+		}
+	}
+
+	return noDestination, false, nil
+}
+
+// lets the caller know which message sent to a USSD channel was the last one of the session, so that the USSD session
+// can be closed when it's sent
+func (s *session) endUSSDSession(run flows.FlowRun, step flows.Step) error {
+	for e := len(s.newEvents) - 1; e >= 0; e-- {
+		msgEvent, isMsg := s.newEvents[e].(*events.MsgCreatedEvent)
+		if isMsg && msgEvent.Msg.Channel() != nil {
+			channelSet, err := s.Assets().GetChannelSet()
+			if err != nil {
+				return err
+			}
+
+			channel := channelSet.FindByUUID(msgEvent.Msg.Channel().UUID)
+			if channel != nil && channel.HasRole(flows.ChannelRoleUSSD) {
+				return run.ApplyEvent(step, nil, events.NewUSSDSessionEndedEvent(&msgEvent.Msg))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSSDSession(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/ussd_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	config := engine.NewConfigBuilder().WithMaxUSSDMsgLength(60).WithClock(utils.NewFixedClock(time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC))).Build()
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+	flow, err := session.Assets().GetFlow(flows.FlowUUID("c4e1a2b3-5d6f-4a7b-8c9d-0e1f2a3b4c5d"))
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	require.NoError(t, session.Start(triggers.NewManualTrigger(nil, contact, flow, nil, time.Now()), nil))
+
+	// returns the last message sent in the last call to the session
+	lastMsg := func() *events.MsgCreatedEvent {
+		for e := len(session.Events()) - 1; e >= 0; e-- {
+			if msgEvent, isMsg := session.Events()[e].(*events.MsgCreatedEvent); isMsg {
+				return msgEvent
+			}
+		}
+		return nil
+	}
+
+	// returns the session ended event from the last call to the session if there is one
+	sessionEnded := func() *events.USSDSessionEndedEvent {
+		for _, event := range session.Events() {
+			if endedEvent, isEnded := event.(*events.USSDSessionEndedEvent); isEnded {
+				return endedEvent
+			}
+		}
+		return nil
+	}
+
+	// quick replies are rendered as a menu as USSD channels can't display them
+	assert.Equal(t, "Welcome to the bank\n1. Balance\n2. Transfer", lastMsg().Msg.Text())
+	assert.Nil(t, lastMsg().Msg.QuickReplies())
+	assert.Equal(t, 0, lastMsg().Page)
+
+	accountPages := []string{
+		"Choose an account\n1. Savings account\n00. Next",
+		"2. Current account\n3. Business account\n0. Back\n00. Next",
+		"4. Joint account\n0. Back",
+	}
+
+	tests := []struct {
+		input string
+		text  string
+		page  int
+	}{
+		{"1", accountPages[0], 1}, // too long for a single screen so is split into pages
+		{"00", accountPages[1], 2},
+		{"00", accountPages[2], 3},
+		{"0", accountPages[1], 2},
+		{"0", accountPages[0], 1},
+		{"0", "Welcome to the bank\n1. Balance\n2. Transfer", 0}, // back from the first page is back to the previous wait
+		{"1", accountPages[0], 1},
+	}
+
+	for _, tc := range tests {
+		// the session is persisted between each step
+		sessionJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, sessionJSON)
+		require.NoError(t, err)
+		require.Equal(t, "ussd", session.Wait().Type())
+
+		msg := events.NewMsgReceivedEvent(flows.NewMsgIn(flows.MsgUUID(session.NewUUID()), urns.URN("tel:+18005555777"), nil, tc.input, nil))
+		require.NoError(t, session.Resume([]flows.Event{msg}))
+
+		assert.Equal(t, flows.SessionStatusWaiting, session.Status(), "status mismatch after input '%s'", tc.input)
+		assert.Equal(t, tc.text, lastMsg().Msg.Text(), "message mismatch after input '%s'", tc.input)
+		assert.Equal(t, tc.page, lastMsg().Page, "page mismatch after input '%s'", tc.input)
+		assert.Nil(t, sessionEnded())
+
+		// going back leaves the waiting step without an exit
+		if tc.page == 0 {
+			path := session.Runs()[0].Path()
+			assert.NotNil(t, path[len(path)-2].LeftOn())
+			assert.Equal(t, flows.ExitUUID(""), path[len(path)-2].ExitUUID())
+		}
+	}
+
+	// picking an account ends the session, which is logged after the final message
+	msg := events.NewMsgReceivedEvent(flows.NewMsgIn(flows.MsgUUID(session.NewUUID()), urns.URN("tel:+18005555777"), nil, "2", nil))
+	require.NoError(t, session.Resume([]flows.Event{msg}))
+
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+	assert.Equal(t, "Your balance is $100", lastMsg().Msg.Text())
+	require.NotNil(t, sessionEnded())
+	assert.Equal(t, lastMsg().Msg.UUID(), sessionEnded().MsgUUID)
+	assert.Equal(t, events.TypeUSSDSessionEnded, session.Events()[len(session.Events())-1].Type())
+}
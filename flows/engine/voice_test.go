@@ -0,0 +1,134 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// what the contact does when the call is waiting: enters digits, makes a recording, or does nothing
+type callResponse struct {
+	digits    string
+	recording string
+}
+
+// simulates a phone call through the given voice flow, persisting the session between each step like a real caller
+// would, and responding to each wait from the given script. Returns the final session and what the contact heard.
+func simulateCall(t *testing.T, assetCache *assets.AssetCache, flowUUID flows.FlowUUID, script []callResponse) (flows.Session, []string) {
+	clock := utils.NewFixedClock(time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC))
+	config := engine.NewConfigBuilder().WithClock(clock).Build()
+
+	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+	flow, err := session.Assets().GetFlow(flowUUID)
+	require.NoError(t, err)
+
+	contact := flows.NewContact(flows.ContactUUID(session.NewUUID()), "Joe", "eng", nil)
+	contact.AddURN(urns.URN("tel:+18005555777"))
+	require.NoError(t, session.Start(triggers.NewManualTrigger(nil, contact, flow, nil, clock.Now()), nil))
+
+	heard := make([]string, 0)
+
+	for {
+		for _, event := range session.Events() {
+			if ivr, isIVR := event.(*events.IVRCreatedEvent); isIVR {
+				if ivr.Msg.Text() != "" {
+					heard = append(heard, ivr.Msg.Text())
+				}
+				for _, attachment := range ivr.Msg.Attachments() {
+					heard = append(heard, fmt.Sprintf("<%s>", attachment.URL()))
+				}
+			}
+		}
+
+		if session.Status() != flows.SessionStatusWaiting || len(script) == 0 {
+			return session, heard
+		}
+
+		// every wait in a voice flow should be a voice wait
+		require.Equal(t, "voice", session.Wait().Type())
+
+		sessionJSON, err := json.Marshal(session)
+		require.NoError(t, err)
+		session, err = engine.ReadSession(assetCache, assets.NewMockAssetServer(), config, test.TestHTTPClient, sessionJSON)
+		require.NoError(t, err)
+
+		var callerEvent flows.Event
+		response := script[0]
+		script = script[1:]
+
+		if response.digits != "" {
+			callerEvent = events.NewMsgReceivedEvent(flows.NewMsgIn(flows.MsgUUID(session.NewUUID()), urns.URN("tel:+18005555777"), nil, response.digits, nil))
+		} else if response.recording != "" {
+			attachments := []flows.Attachment{flows.Attachment("audio/wav:" + response.recording)}
+			callerEvent = events.NewMsgReceivedEvent(flows.NewMsgIn(flows.MsgUUID(session.NewUUID()), urns.URN("tel:+18005555777"), nil, "", attachments))
+		} else {
+			clock.SetNow(session.Wait().TimeoutOn().Add(time.Second))
+			callerEvent = events.NewWaitTimedOutEvent()
+		}
+
+		require.NoError(t, session.Resume([]flows.Event{callerEvent}))
+	}
+}
+
+func TestVoiceFlow(t *testing.T) {
+	sessionAssets, err := ioutil.ReadFile("testdata/voice_test.json")
+	require.NoError(t, err)
+
+	assetCache := assets.NewAssetCache(100, 5)
+	require.NoError(t, assetCache.Include(json.RawMessage(sessionAssets)))
+
+	flowUUID := flows.FlowUUID("3a8b2c4d-6e5f-4a7b-9c1d-2e3f4a5b6c7d")
+	welcome := "Welcome Joe. Press 1 for sales or 2 for support, followed by the hash key"
+
+	tests := []struct {
+		script []callResponse
+		heard  []string
+	}{
+		{
+			[]callResponse{{digits: "1#"}, {recording: "http://example.com/msg.wav"}},
+			[]string{welcome, "Please leave a message after the tone", "You said", "<http://example.com/msg.wav>", "Goodbye"},
+		},
+		{
+			[]callResponse{{digits: "1#"}, {digits: "5"}},
+			[]string{welcome, "Please leave a message after the tone", "Goodbye"},
+		},
+		{
+			[]callResponse{{digits: "2#"}},
+			[]string{welcome, "Goodbye"},
+		},
+		{
+			[]callResponse{{}},
+			[]string{welcome, "Goodbye"},
+		},
+	}
+
+	for _, tc := range tests {
+		session, heard := simulateCall(t, assetCache, flowUUID, tc.script)
+
+		assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+		assert.Equal(t, tc.heard, heard)
+	}
+
+	// voice waits tell the caller what to collect
+	session, _ := simulateCall(t, assetCache, flowUUID, nil)
+	waitEvent := session.Events()[len(session.Events())-1].(*events.VoiceWaitEvent)
+	waitEventJSON, err := json.Marshal(waitEvent)
+	require.NoError(t, err)
+	assert.Contains(t, string(waitEventJSON), `"hint":{"type":"digits","count":1,"terminated_by":"#"}`)
+	assert.NotNil(t, waitEvent.TimeoutOn)
+}
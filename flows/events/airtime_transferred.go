@@ -0,0 +1,49 @@
+package events
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeAirtimeTransferred is the type of our airtime transferred event
+const TypeAirtimeTransferred string = "airtime_transferred"
+
+// AirtimeTransferredEvent events are created when airtime has been transferred to the contact, or when a transfer
+// was attempted but failed. The transfer records the amount we tried to transfer and the amount that was actually
+// transferred in the currency of the recipient's network.
+//
+//   {
+//     "type": "airtime_transferred",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "transfer": {
+//       "recipient": "tel:+12065551212",
+//       "currency": "USD",
+//       "desired_amount": 1.20,
+//       "actual_amount": 1.00,
+//       "status": "success"
+//     }
+//   }
+//
+// @event airtime_transferred
+type AirtimeTransferredEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	Transfer *flows.AirtimeTransfer `json:"transfer" validate:"required"`
+}
+
+// NewAirtimeTransferredEvent returns a new airtime transferred event for the given transfer
+func NewAirtimeTransferredEvent(transfer *flows.AirtimeTransfer) *AirtimeTransferredEvent {
+	return &AirtimeTransferredEvent{
+		BaseEvent: NewBaseEvent(),
+		Transfer:  transfer,
+	}
+}
+
+// Type returns the type of this event
+func (e *AirtimeTransferredEvent) Type() string { return TypeAirtimeTransferred }
+
+// Apply applies this event to the given run
+func (e *AirtimeTransferredEvent) Apply(run flows.FlowRun) error {
+	run.SetAirtimeTransfer(e.Transfer)
+	return nil
+}
@@ -5,43 +5,49 @@ import (
 	"time"
 )
 
-type baseEvent struct {
-	CreatedOn_  time.Time      `json:"created_on" validate:"required"`
+// BaseEvent is the base of all event types and can be embedded by event types registered by other packages
+type BaseEvent struct {
+	CreatedOn_  time.Time      `json:"created_on"`
 	StepUUID_   flows.StepUUID `json:"step_uuid,omitempty" validate:"omitempty,uuid4"`
 	FromCaller_ bool           `json:"-"`
 }
 
-func newBaseEvent() baseEvent {
-	return baseEvent{CreatedOn_: time.Now().UTC()}
+// NewBaseEvent creates a new base event. Its created_on is set from the session clock when it's applied, or for caller
+// events which don't already have one, when the session receives it.
+func NewBaseEvent() BaseEvent {
+	return BaseEvent{}
 }
 
-func (e *baseEvent) CreatedOn() time.Time        { return e.CreatedOn_ }
-func (e *baseEvent) SetCreatedOn(time time.Time) { e.CreatedOn_ = time }
+func (e *BaseEvent) CreatedOn() time.Time        { return e.CreatedOn_ }
+func (e *BaseEvent) SetCreatedOn(time time.Time) { e.CreatedOn_ = time }
 
-func (e *baseEvent) StepUUID() flows.StepUUID            { return e.StepUUID_ }
-func (e *baseEvent) SetStepUUID(stepUUID flows.StepUUID) { e.StepUUID_ = stepUUID }
+func (e *BaseEvent) StepUUID() flows.StepUUID            { return e.StepUUID_ }
+func (e *BaseEvent) SetStepUUID(stepUUID flows.StepUUID) { e.StepUUID_ = stepUUID }
 
-func (e *baseEvent) FromCaller() bool              { return e.FromCaller_ }
-func (e *baseEvent) SetFromCaller(fromCaller bool) { e.FromCaller_ = fromCaller }
+func (e *BaseEvent) FromCaller() bool              { return e.FromCaller_ }
+func (e *BaseEvent) SetFromCaller(fromCaller bool) { e.FromCaller_ = fromCaller }
 
-type callerOnlyEvent struct{}
+// CallerOnlyEvent can be embedded by event types which can only be sent by callers
+type CallerOnlyEvent struct{}
 
 // AllowedOrigin determines where this event type can originate
-func (e *callerOnlyEvent) AllowedOrigin() flows.EventOrigin { return flows.EventOriginCaller }
+func (e *CallerOnlyEvent) AllowedOrigin() flows.EventOrigin { return flows.EventOriginCaller }
 
-type engineOnlyEvent struct{}
+// EngineOnlyEvent can be embedded by event types which can only be generated by the engine
+type EngineOnlyEvent struct{}
 
 // AllowedOrigin determines where this event type can originate
-func (e *engineOnlyEvent) AllowedOrigin() flows.EventOrigin { return flows.EventOriginEngine }
+func (e *EngineOnlyEvent) AllowedOrigin() flows.EventOrigin { return flows.EventOriginEngine }
 
 // Validate validates our event is valid and has all the assets it needs. We assume engine generated events are valid.
-func (e *engineOnlyEvent) Validate(assets flows.SessionAssets) error {
+func (e *EngineOnlyEvent) Validate(assets flows.SessionAssets) error {
 	return nil
 }
 
-type callerOrEngineEvent struct{}
+// CallerOrEngineEvent can be embedded by event types which can be sent by callers or generated by the engine
+type CallerOrEngineEvent struct{}
 
 // AllowedOrigin determines where this event type can originate
-func (e *callerOrEngineEvent) AllowedOrigin() flows.EventOrigin {
+func (e *CallerOrEngineEvent) AllowedOrigin() flows.EventOrigin {
 	return flows.EventOriginCaller | flows.EventOriginEngine
 }
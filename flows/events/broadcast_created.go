@@ -40,8 +40,8 @@ type BroadcastTranslation struct {
 //
 // @event broadcast_created
 type BroadcastCreatedEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	Translations map[utils.Language]*BroadcastTranslation `json:"translations,min=1" validate:"dive"`
 	BaseLanguage utils.Language                           `json:"base_language" validate:"required"`
@@ -53,7 +53,7 @@ type BroadcastCreatedEvent struct {
 // NewBroadcastCreatedEvent creates a new outgoing msg event for the given recipients
 func NewBroadcastCreatedEvent(translations map[utils.Language]*BroadcastTranslation, baseLanguage utils.Language, urns []urns.URN, contacts []*flows.ContactReference, groups []*flows.GroupReference) *BroadcastCreatedEvent {
 	event := BroadcastCreatedEvent{
-		baseEvent:    newBaseEvent(),
+		BaseEvent:    NewBaseEvent(),
 		Translations: translations,
 		BaseLanguage: baseLanguage,
 		URNs:         urns,
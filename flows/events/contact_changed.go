@@ -20,8 +20,8 @@ const TypeContactChanged string = "contact_changed"
 //
 // @event contact_changed
 type ContactChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Contact json.RawMessage `json:"contact"`
 }
@@ -18,8 +18,8 @@ const TypeContactChannelChanged string = "contact_channel_changed"
 //
 // @event contact_channel_changed
 type ContactChannelChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Channel *flows.ChannelReference `json:"channel" validate:"required"`
 }
@@ -27,7 +27,7 @@ type ContactChannelChangedEvent struct {
 // NewContactChannelChangedEvent returns a new preferred channel event
 func NewContactChannelChangedEvent(channel *flows.ChannelReference) *ContactChannelChangedEvent {
 	return &ContactChannelChangedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Channel:   channel,
 	}
 }
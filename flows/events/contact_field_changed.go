@@ -20,8 +20,8 @@ const TypeContactFieldChanged string = "contact_field_changed"
 //
 // @event contact_field_changed
 type ContactFieldChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Field *flows.FieldReference `json:"field" validate:"required"`
 	Value string                `json:"value" validate:"required"`
@@ -30,7 +30,7 @@ type ContactFieldChangedEvent struct {
 // NewContactFieldChangedEvent returns a new save to contact event
 func NewContactFieldChangedEvent(field *flows.FieldReference, value string) *ContactFieldChangedEvent {
 	return &ContactFieldChangedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Field:     field,
 		Value:     value,
 	}
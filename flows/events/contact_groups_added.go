@@ -19,8 +19,8 @@ const TypeContactGroupsAdded string = "contact_groups_added"
 //
 // @event contact_groups_added
 type ContactGroupsAddedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Groups []*flows.GroupReference `json:"groups" validate:"required,min=1,dive"`
 }
@@ -28,7 +28,7 @@ type ContactGroupsAddedEvent struct {
 // NewContactGroupsAddedEvent returns a new contact_groups_added event
 func NewContactGroupsAddedEvent(groups []*flows.GroupReference) *ContactGroupsAddedEvent {
 	return &ContactGroupsAddedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Groups:    groups,
 	}
 }
@@ -20,8 +20,8 @@ const TypeContactGroupsRemoved string = "contact_groups_removed"
 //
 // @event contact_groups_removed
 type ContactGroupsRemovedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Groups []*flows.GroupReference `json:"groups" validate:"required,min=1,dive"`
 }
@@ -29,7 +29,7 @@ type ContactGroupsRemovedEvent struct {
 // NewContactGroupsRemovedEvent returns a new remove from group event
 func NewContactGroupsRemovedEvent(groups []*flows.GroupReference) *ContactGroupsRemovedEvent {
 	return &ContactGroupsRemovedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Groups:    groups,
 	}
 }
@@ -20,8 +20,8 @@ const TypeContactLanguageChanged string = "contact_language_changed"
 //
 // @event contact_language_changed
 type ContactLanguageChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Language string `json:"language"`
 }
@@ -29,7 +29,7 @@ type ContactLanguageChangedEvent struct {
 // NewContactLanguageChangedEvent returns a new contact language changed event
 func NewContactLanguageChangedEvent(language string) *ContactLanguageChangedEvent {
 	return &ContactLanguageChangedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Language:  language,
 	}
 }
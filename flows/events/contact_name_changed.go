@@ -19,8 +19,8 @@ const TypeContactNameChanged string = "contact_name_changed"
 //
 // @event contact_name_changed
 type ContactNameChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Name string `json:"name"`
 }
@@ -28,7 +28,7 @@ type ContactNameChangedEvent struct {
 // NewContactNameChangedEvent returns a new contact name changed event
 func NewContactNameChangedEvent(name string) *ContactNameChangedEvent {
 	return &ContactNameChangedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Name:      name,
 	}
 }
@@ -20,8 +20,8 @@ const TypeContactTimezoneChanged string = "contact_timezone_changed"
 //
 // @event contact_timezone_changed
 type ContactTimezoneChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Timezone string `json:"timezone"`
 }
@@ -29,7 +29,7 @@ type ContactTimezoneChangedEvent struct {
 // NewContactTimezoneChangedEvent returns a new contact timezone changed event
 func NewContactTimezoneChangedEvent(timezone string) *ContactTimezoneChangedEvent {
 	return &ContactTimezoneChangedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Timezone:  timezone,
 	}
 }
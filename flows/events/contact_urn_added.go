@@ -20,15 +20,15 @@ const TypeContactURNAdded string = "contact_urn_added"
 //
 // @event contact_urn_added
 type ContactURNAddedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	URN urns.URN `json:"urn" validate:"urn"`
 }
 
 // NewURNAddedEvent returns a new add URN event
 func NewURNAddedEvent(urn urns.URN) *ContactURNAddedEvent {
-	return &ContactURNAddedEvent{baseEvent: newBaseEvent(), URN: urn}
+	return &ContactURNAddedEvent{BaseEvent: NewBaseEvent(), URN: urn}
 }
 
 // Type returns the type of this event
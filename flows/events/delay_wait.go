@@ -0,0 +1,44 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeDelayWait is the type of our delay wait event
+const TypeDelayWait string = "delay_wait"
+
+// DelayWaitEvent events are created when a flow pauses until a point in time. The caller should resume the flow
+// with a [wait_timed_out](#event:wait_timed_out) event at that time. Messages received in the meantime don't
+// resume the flow.
+//
+//   {
+//     "type": "delay_wait",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "timeout_on": "2006-01-05T15:04:05Z"
+//   }
+//
+// @event delay_wait
+type DelayWaitEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	TimeoutOn time.Time `json:"timeout_on" validate:"required"`
+}
+
+// NewDelayWait returns a new delay wait event which resumes at the given time
+func NewDelayWait(timeoutOn time.Time) *DelayWaitEvent {
+	return &DelayWaitEvent{
+		BaseEvent: NewBaseEvent(),
+		TimeoutOn: timeoutOn,
+	}
+}
+
+// Type returns the type of this event
+func (e *DelayWaitEvent) Type() string { return TypeDelayWait }
+
+// Apply applies this event to the given run
+func (e *DelayWaitEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
@@ -17,8 +17,8 @@ const TypeEmailCreated string = "email_created"
 //
 // @event email_created
 type EmailCreatedEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	Addresses []string `json:"addresses" validate:"required,min=1"`
 	Subject   string   `json:"subject" validate:"required"`
@@ -28,7 +28,7 @@ type EmailCreatedEvent struct {
 // NewEmailCreatedEvent returns a new email event with the passed in subject, body and emails
 func NewEmailCreatedEvent(addresses []string, subject string, body string) *EmailCreatedEvent {
 	return &EmailCreatedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Addresses: addresses,
 		Subject:   subject,
 		Body:      body,
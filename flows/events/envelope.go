@@ -7,6 +7,55 @@ import (
 	"github.com/nyaruka/goflow/utils"
 )
 
+var registeredTypes = map[string](func() flows.Event){}
+
+// RegisterType registers a new type of event so that it can be read from JSON. The given function should return a
+// new empty instance of the event type which is then populated from JSON. Where an event can originate from, and how
+// it is validated when sent by callers, is determined by the event type itself, typically by embedding one of
+// CallerOnlyEvent, EngineOnlyEvent or CallerOrEngineEvent.
+func RegisterType(name string, initFunc func() flows.Event) {
+	registeredTypes[name] = initFunc
+}
+
+func init() {
+	RegisterType(TypeAirtimeTransferred, func() flows.Event { return &AirtimeTransferredEvent{} })
+	RegisterType(TypeBroadcastCreated, func() flows.Event { return &BroadcastCreatedEvent{} })
+	RegisterType(TypeContactChanged, func() flows.Event { return &ContactChangedEvent{} })
+	RegisterType(TypeContactChannelChanged, func() flows.Event { return &ContactChannelChangedEvent{} })
+	RegisterType(TypeContactFieldChanged, func() flows.Event { return &ContactFieldChangedEvent{} })
+	RegisterType(TypeContactGroupsAdded, func() flows.Event { return &ContactGroupsAddedEvent{} })
+	RegisterType(TypeContactGroupsRemoved, func() flows.Event { return &ContactGroupsRemovedEvent{} })
+	RegisterType(TypeContactLanguageChanged, func() flows.Event { return &ContactLanguageChangedEvent{} })
+	RegisterType(TypeContactNameChanged, func() flows.Event { return &ContactNameChangedEvent{} })
+	RegisterType(TypeContactTimezoneChanged, func() flows.Event { return &ContactTimezoneChangedEvent{} })
+	RegisterType(TypeContactURNAdded, func() flows.Event { return &ContactURNAddedEvent{} })
+	RegisterType(TypeDelayWait, func() flows.Event { return &DelayWaitEvent{} })
+	RegisterType(TypeEmailCreated, func() flows.Event { return &EmailCreatedEvent{} })
+	RegisterType(TypeEnvironmentChanged, func() flows.Event { return &EnvironmentChangedEvent{} })
+	RegisterType(TypeError, func() flows.Event { return &ErrorEvent{} })
+	RegisterType(TypeExecutionLimitReached, func() flows.Event { return &ExecutionLimitReachedEvent{} })
+	RegisterType(TypeFlowTriggered, func() flows.Event { return &FlowTriggeredEvent{} })
+	RegisterType(TypeIVRCreated, func() flows.Event { return &IVRCreatedEvent{} })
+	RegisterType(TypeInputLabelsAdded, func() flows.Event { return &InputLabelsAddedEvent{} })
+	RegisterType(TypeMsgCreated, func() flows.Event { return &MsgCreatedEvent{} })
+	RegisterType(TypeMsgReceived, func() flows.Event { return &MsgReceivedEvent{} })
+	RegisterType(TypeMsgWait, func() flows.Event { return &MsgWaitEvent{} })
+	RegisterType(TypeNothingWait, func() flows.Event { return &NothingWaitEvent{} })
+	RegisterType(TypeRunExpired, func() flows.Event { return &RunExpiredEvent{} })
+	RegisterType(TypeRunResultChanged, func() flows.Event { return &RunResultChangedEvent{} })
+	RegisterType(TypeSessionInterrupted, func() flows.Event { return &SessionInterruptedEvent{} })
+	RegisterType(TypeSessionTriggered, func() flows.Event { return &SessionTriggeredEvent{} })
+	RegisterType(TypeSignalReceived, func() flows.Event { return &SignalReceivedEvent{} })
+	RegisterType(TypeSignalWait, func() flows.Event { return &SignalWaitEvent{} })
+	RegisterType(TypeTicketClosed, func() flows.Event { return &TicketClosedEvent{} })
+	RegisterType(TypeTicketOpened, func() flows.Event { return &TicketOpenedEvent{} })
+	RegisterType(TypeTicketWait, func() flows.Event { return &TicketWaitEvent{} })
+	RegisterType(TypeUSSDSessionEnded, func() flows.Event { return &USSDSessionEndedEvent{} })
+	RegisterType(TypeVoiceWait, func() flows.Event { return &VoiceWaitEvent{} })
+	RegisterType(TypeWaitTimedOut, func() flows.Event { return &WaitTimedOutEvent{} })
+	RegisterType(TypeWebhookCalled, func() flows.Event { return &WebhookCalledEvent{} })
+}
+
 // ReadEvents reads the events from the given envelopes
 func ReadEvents(envelopes []*utils.TypedEnvelope) ([]flows.Event, error) {
 	events := make([]flows.Event, len(envelopes))
@@ -21,63 +70,14 @@ func ReadEvents(envelopes []*utils.TypedEnvelope) ([]flows.Event, error) {
 	return events, nil
 }
 
-// EventFromEnvelope reads a single event from the given envelope
+// EventFromEnvelope reads a single event of a registered type from the given envelope
 func EventFromEnvelope(envelope *utils.TypedEnvelope) (flows.Event, error) {
-	var event flows.Event
-
-	switch envelope.Type {
-	case TypeBroadcastCreated:
-		event = &BroadcastCreatedEvent{}
-	case TypeContactChanged:
-		event = &ContactChangedEvent{}
-	case TypeContactChannelChanged:
-		event = &ContactChannelChangedEvent{}
-	case TypeContactFieldChanged:
-		event = &ContactFieldChangedEvent{}
-	case TypeContactGroupsAdded:
-		event = &ContactGroupsAddedEvent{}
-	case TypeContactGroupsRemoved:
-		event = &ContactGroupsRemovedEvent{}
-	case TypeContactLanguageChanged:
-		event = &ContactLanguageChangedEvent{}
-	case TypeContactNameChanged:
-		event = &ContactNameChangedEvent{}
-	case TypeContactTimezoneChanged:
-		event = &ContactTimezoneChangedEvent{}
-	case TypeContactURNAdded:
-		event = &ContactURNAddedEvent{}
-	case TypeEmailCreated:
-		event = &EmailCreatedEvent{}
-	case TypeEnvironmentChanged:
-		event = &EnvironmentChangedEvent{}
-	case TypeError:
-		event = &ErrorEvent{}
-	case TypeFlowTriggered:
-		event = &FlowTriggeredEvent{}
-	case TypeInputLabelsAdded:
-		event = &InputLabelsAddedEvent{}
-	case TypeMsgCreated:
-		event = &MsgCreatedEvent{}
-	case TypeMsgReceived:
-		event = &MsgReceivedEvent{}
-	case TypeMsgWait:
-		event = &MsgWaitEvent{}
-	case TypeNothingWait:
-		event = &NothingWaitEvent{}
-	case TypeRunExpired:
-		event = &RunExpiredEvent{}
-	case TypeRunResultChanged:
-		event = &RunResultChangedEvent{}
-	case TypeSessionTriggered:
-		event = &SessionTriggeredEvent{}
-	case TypeWaitTimedOut:
-		event = &WaitTimedOutEvent{}
-	case TypeWebhookCalled:
-		event = &WebhookCalledEvent{}
-	default:
+	initFunc := registeredTypes[envelope.Type]
+	if initFunc == nil {
 		return nil, fmt.Errorf("unknown event type: %s", envelope.Type)
 	}
 
+	event := initFunc()
 	return event, utils.UnmarshalAndValidate(envelope.Data, event, fmt.Sprintf("event[type=%s]", envelope.Type))
 }
 
@@ -25,8 +25,8 @@ const TypeEnvironmentChanged string = "environment_changed"
 //
 // @event environment_changed
 type EnvironmentChangedEvent struct {
-	baseEvent
-	callerOnlyEvent
+	BaseEvent
+	CallerOnlyEvent
 
 	Environment json.RawMessage `json:"environment"`
 }
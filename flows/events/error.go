@@ -16,8 +16,8 @@ const TypeError string = "error"
 //
 // @event error
 type ErrorEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	Text  string `json:"text" validate:"required"`
 	Fatal bool   `json:"fatal"`
@@ -26,7 +26,7 @@ type ErrorEvent struct {
 // NewErrorEvent returns a new error event for the passed in error
 func NewErrorEvent(err error) *ErrorEvent {
 	return &ErrorEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Text:      err.Error(),
 	}
 }
@@ -34,7 +34,7 @@ func NewErrorEvent(err error) *ErrorEvent {
 // NewFatalErrorEvent returns a new fatal error event for the passed in error
 func NewFatalErrorEvent(err error) *ErrorEvent {
 	return &ErrorEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Text:      err.Error(),
 		Fatal:     true,
 	}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeExecutionLimitReached is the type of our execution limit reached event
+const TypeExecutionLimitReached string = "execution_limit_reached"
+
+// ExecutionLimitReachedEvent events are created when a session hits one of the execution limits of the engine
+// config, which is always fatal. The limit is the name of the config option which was reached, one of
+// `max_steps_per_call`, `max_runs_per_session` or `max_subflow_depth`.
+//
+//   {
+//     "type": "execution_limit_reached",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "limit": "max_steps_per_call",
+//     "text": "maximum of 100 steps per call, stopping execution before entering 'd2a4052a-3fa9-4608-ab3e-5b9631440447'"
+//   }
+//
+// @event execution_limit_reached
+type ExecutionLimitReachedEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	Limit string `json:"limit" validate:"required"`
+	Text  string `json:"text"  validate:"required"`
+}
+
+// NewExecutionLimitReachedEvent returns a new execution limit reached event for the given limit
+func NewExecutionLimitReachedEvent(limit string, text string) *ExecutionLimitReachedEvent {
+	return &ExecutionLimitReachedEvent{
+		BaseEvent: NewBaseEvent(),
+		Limit:     limit,
+		Text:      text,
+	}
+}
+
+// Type returns the type of this event
+func (e *ExecutionLimitReachedEvent) Type() string { return TypeExecutionLimitReached }
+
+// Apply applies this event to the given run
+func (e *ExecutionLimitReachedEvent) Apply(run flows.FlowRun) error {
+	run.Exit(flows.RunStatusErrored)
+	return nil
+}
@@ -18,8 +18,8 @@ const TypeFlowTriggered string = "flow_triggered"
 //
 // @event flow_triggered
 type FlowTriggeredEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	Flow          *flows.FlowReference `json:"flow" validate:"required"`
 	ParentRunUUID flows.RunUUID        `json:"parent_run_uuid" validate:"omitempty,uuid4"`
@@ -28,7 +28,7 @@ type FlowTriggeredEvent struct {
 // NewFlowTriggeredEvent returns a new flow triggered event for the passed in flow and parent run
 func NewFlowTriggeredEvent(flow *flows.FlowReference, parentRunUUID flows.RunUUID) *FlowTriggeredEvent {
 	return &FlowTriggeredEvent{
-		baseEvent:     newBaseEvent(),
+		BaseEvent:     NewBaseEvent(),
 		Flow:          flow,
 		ParentRunUUID: parentRunUUID,
 	}
@@ -16,8 +16,8 @@ const TypeInputLabelsAdded string = "input_labels_added"
 //
 // @event input_labels_added
 type InputLabelsAddedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
 	InputUUID flows.InputUUID         `json:"input_uuid" validate:"required,uuid4"`
 	Labels    []*flows.LabelReference `json:"labels" validate:"required,min=1,dive"`
@@ -26,7 +26,7 @@ type InputLabelsAddedEvent struct {
 // NewInputLabelsAddedEvent returns a new add to group event
 func NewInputLabelsAddedEvent(inputUUID flows.InputUUID, labels []*flows.LabelReference) *InputLabelsAddedEvent {
 	return &InputLabelsAddedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		InputUUID: inputUUID,
 		Labels:    labels,
 	}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeIVRCreated is the type of our IVR created event
+const TypeIVRCreated string = "ivr_created"
+
+// IVRCreatedEvent events are created when an action wants to speak text or play audio to the contact on the current
+// phone call of a voice flow. Spoken text is included as the message text, and audio to be played is included as an
+// attachment.
+//
+//   {
+//     "type": "ivr_created",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "msg": {
+//       "uuid": "2d611e17-fb22-457f-b802-b8f7ec5cda5b",
+//       "channel": {"uuid": "61602f3e-f603-4c70-8a8f-c477505bf4bf", "name": "Twilio"},
+//       "urn": "tel:+12065551212",
+//       "text": "Please enter your PIN followed by the hash key"
+//     }
+//   }
+//
+// @event ivr_created
+type IVRCreatedEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	Msg flows.MsgOut `json:"msg" validate:"required,dive"`
+}
+
+// NewIVRCreatedEvent creates a new IVR created event
+func NewIVRCreatedEvent(msg *flows.MsgOut) *IVRCreatedEvent {
+	return &IVRCreatedEvent{
+		BaseEvent: NewBaseEvent(),
+		Msg:       *msg,
+	}
+}
+
+// Type returns the type of this event
+func (e *IVRCreatedEvent) Type() string { return TypeIVRCreated }
+
+// Apply applies this event to the given run
+func (e *IVRCreatedEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 // TypeMsgCreated is a constant for incoming messages
 const TypeMsgCreated string = "msg_created"
 
-// MsgCreatedEvent events are used for replies to the session contact.
+// MsgCreatedEvent events are used for replies to the session contact. Messages to USSD channels which are too long
+// for a single screen are split into pages, in which case the message text is the current page, `page` is its
+// number and `pages` are all the pages.
 //
 //   {
 //     "type": "msg_created",
@@ -23,20 +25,32 @@ const TypeMsgCreated string = "msg_created"
 //
 // @event msg_created
 type MsgCreatedEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
-	Msg flows.MsgOut `json:"msg" validate:"required,dive"`
+	Msg   flows.MsgOut `json:"msg" validate:"required,dive"`
+	Page  int          `json:"page,omitempty"`
+	Pages []string     `json:"pages,omitempty"`
 }
 
 // NewMsgCreatedEvent creates a new outgoing msg event to a single contact
 func NewMsgCreatedEvent(msg *flows.MsgOut) *MsgCreatedEvent {
 	return &MsgCreatedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Msg:       *msg,
 	}
 }
 
+// NewPagedMsgCreatedEvent creates a new outgoing msg event for the given page of a message split into pages
+func NewPagedMsgCreatedEvent(msg *flows.MsgOut, page int, pages []string) *MsgCreatedEvent {
+	return &MsgCreatedEvent{
+		BaseEvent: NewBaseEvent(),
+		Msg:       *msg,
+		Page:      page,
+		Pages:     pages,
+	}
+}
+
 // Type returns the type of this event
 func (e *MsgCreatedEvent) Type() string { return TypeMsgCreated }
 
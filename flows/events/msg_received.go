@@ -25,8 +25,8 @@ const TypeMsgReceived string = "msg_received"
 //
 // @event msg_received
 type MsgReceivedEvent struct {
-	baseEvent
-	callerOnlyEvent
+	BaseEvent
+	CallerOnlyEvent
 
 	Msg flows.MsgIn `json:"msg" validate:"required,dive"`
 }
@@ -34,7 +34,7 @@ type MsgReceivedEvent struct {
 // NewMsgReceivedEvent creates a new incoming msg event for the passed in channel, URN and text
 func NewMsgReceivedEvent(msg *flows.MsgIn) *MsgReceivedEvent {
 	return &MsgReceivedEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		Msg:       *msg,
 	}
 }
@@ -1,37 +1,52 @@
 package events
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/waits/hints"
+	"github.com/nyaruka/goflow/utils"
 )
 
 // TypeMsgWait is the type of our msg wait event
 const TypeMsgWait string = "msg_wait"
 
 // MsgWaitEvent events are created when a flow pauses waiting for a response from
-// a contact. If a timeout is set, then the caller should resume the flow after
-// the number of seconds in the timeout to resume it.
+// a contact. If a timeout is set, then the caller should resume the flow at `timeout_on`
+// with a `wait_timed_out` event. If the wait's timeout is in business hours and the environment
+// has a schedule, then `timeout_on` only counts the working hours of that schedule, evaluated in
+// the timezone of the run, e.g. a 2 hour timeout which begins at 4pm on a Friday could end at
+// 10am on the Monday. If a hint is set, then it describes
+// the kind of input expected, e.g. `text`, `digits`, `image`, `audio`, `video`, `location`
+// or `date`, so that the channel can adapt how it asks for it.
 //
 //   {
 //     "type": "msg_wait",
 //     "created_on": "2006-01-02T15:04:05Z",
-//     "timeout": 300
+//     "timeout_on": "2006-01-02T16:04:05Z",
+//     "hint": {
+//       "type": "digits",
+//       "count": 4,
+//       "terminated_by": "#"
+//     }
 //   }
 //
 // @event msg_wait
 type MsgWaitEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	TimeoutOn *time.Time `json:"timeout_on,omitempty"`
+	Hint      flows.Hint `json:"hint,omitempty"`
 }
 
-// NewMsgWait returns a new msg wait with the passed in timeout
-func NewMsgWait(timeoutOn *time.Time) *MsgWaitEvent {
+// NewMsgWait returns a new msg wait with the passed in timeout and hint
+func NewMsgWait(timeoutOn *time.Time, hint flows.Hint) *MsgWaitEvent {
 	return &MsgWaitEvent{
-		baseEvent: newBaseEvent(),
+		BaseEvent: NewBaseEvent(),
 		TimeoutOn: timeoutOn,
+		Hint:      hint,
 	}
 }
 
@@ -42,3 +57,33 @@ func (e *MsgWaitEvent) Type() string { return TypeMsgWait }
 func (e *MsgWaitEvent) Apply(run flows.FlowRun) error {
 	return nil
 }
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type msgWaitEventEnvelope struct {
+	BaseEvent
+
+	TimeoutOn *time.Time           `json:"timeout_on,omitempty"`
+	Hint      *utils.TypedEnvelope `json:"hint,omitempty"`
+}
+
+// UnmarshalJSON unmarshals a msg wait event from the given JSON
+func (e *MsgWaitEvent) UnmarshalJSON(data []byte) error {
+	envelope := &msgWaitEventEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return err
+	}
+
+	e.BaseEvent = envelope.BaseEvent
+	e.TimeoutOn = envelope.TimeoutOn
+
+	var err error
+	if envelope.Hint != nil {
+		if e.Hint, err = hints.HintFromEnvelope(envelope.Hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 // TypeRunExpired is the type of our flow expired event
 const TypeRunExpired string = "run_expired"
 
-// RunExpiredEvent events are sent by the caller to notify the engine that a run has expired
+// RunExpiredEvent events are sent by the caller to notify the engine that a run has expired. The expired run can be
+// the waiting run or any of its parents in the session. The expired run and all of its children are exited with a
+// status of expired, and if the expired run has a parent, that parent resumes at the node which started the expired run.
 //
 //   {
 //     "type": "run_expired",
@@ -19,8 +21,8 @@ const TypeRunExpired string = "run_expired"
 //
 // @event run_expired
 type RunExpiredEvent struct {
-	baseEvent
-	callerOnlyEvent
+	BaseEvent
+	CallerOnlyEvent
 
 	RunUUID flows.RunUUID `json:"run_uuid"    validate:"required,uuid4"`
 }
@@ -35,10 +37,35 @@ func (e *RunExpiredEvent) Validate(assets flows.SessionAssets) error {
 
 // Apply applies this event to the given run
 func (e *RunExpiredEvent) Apply(run flows.FlowRun) error {
-	if run.UUID() != e.RunUUID {
-		return fmt.Errorf("only the current run can be expired")
+	if run.Status() != flows.RunStatusWaiting {
+		return fmt.Errorf("can only be applied to waiting runs")
 	}
 
-	run.Exit(flows.RunStatusExpired)
+	// find the run being expired, which must be this run or one of its parents
+	var expiring flows.FlowRun
+	for r := run; r != nil; r = r.ParentInSession() {
+		if r.UUID() == e.RunUUID {
+			expiring = r
+			break
+		}
+	}
+	if expiring == nil {
+		return fmt.Errorf("only the waiting run or one of its parents can be expired")
+	}
+
+	if expiring.ExpiresOn() == nil {
+		return fmt.Errorf("can't expire run which doesn't have an expiration")
+	}
+	if run.Environment().Now().Before(*expiring.ExpiresOn()) {
+		return fmt.Errorf("can't expire run before its expiration")
+	}
+
+	// exit the waiting run and every run up to and including the expired one
+	for r := run; ; r = r.ParentInSession() {
+		r.Exit(flows.RunStatusExpired)
+		if r == expiring {
+			break
+		}
+	}
 	return nil
 }
@@ -1,8 +1,10 @@
 package events
 
 import (
-	"github.com/nyaruka/goflow/flows"
+	"encoding/json"
 	"time"
+
+	"github.com/nyaruka/goflow/flows"
 )
 
 // TypeRunResultChanged is the type of our run result event
@@ -10,7 +12,8 @@ const TypeRunResultChanged string = "run_result_changed"
 
 // RunResultChangedEvent events are created when a result is saved. They contain not only
 // the name, value and category of the result, but also the UUID of the node where
-// the result was generated.
+// the result was generated. Results created by actions such as [call_classifier](#action:call_classifier)
+// may also have extra data.
 //
 //   {
 //     "type": "run_result_changed",
@@ -25,27 +28,29 @@ const TypeRunResultChanged string = "run_result_changed"
 //
 // @event run_result_changed
 type RunResultChangedEvent struct {
-	baseEvent
-	callerOrEngineEvent
+	BaseEvent
+	CallerOrEngineEvent
 
-	Name              string         `json:"name" validate:"required"`
-	Value             string         `json:"value"`
-	Category          string         `json:"category"`
-	CategoryLocalized string         `json:"category_localized,omitempty"`
-	NodeUUID          flows.NodeUUID `json:"node_uuid" validate:"required,uuid4"`
-	Input             *string        `json:"input,omitempty"`
+	Name              string          `json:"name" validate:"required"`
+	Value             string          `json:"value"`
+	Category          string          `json:"category"`
+	CategoryLocalized string          `json:"category_localized,omitempty"`
+	NodeUUID          flows.NodeUUID  `json:"node_uuid" validate:"required,uuid4"`
+	Input             *string         `json:"input,omitempty"`
+	Extra             json.RawMessage `json:"extra,omitempty"`
 }
 
 // NewRunResultChangedEvent returns a new save result event for the passed in values
-func NewRunResultChangedEvent(name string, value string, categoryName string, categoryLocalized string, node flows.NodeUUID, input *string) *RunResultChangedEvent {
+func NewRunResultChangedEvent(name string, value string, categoryName string, categoryLocalized string, node flows.NodeUUID, input *string, extra json.RawMessage) *RunResultChangedEvent {
 	return &RunResultChangedEvent{
-		baseEvent:         newBaseEvent(),
+		BaseEvent:         NewBaseEvent(),
 		Name:              name,
 		Value:             value,
 		Category:          categoryName,
 		CategoryLocalized: categoryLocalized,
 		NodeUUID:          node,
 		Input:             input,
+		Extra:             extra,
 	}
 }
 
@@ -59,6 +64,6 @@ func (e *RunResultChangedEvent) Validate(assets flows.SessionAssets) error {
 
 // Apply applies this event to the given run
 func (e *RunResultChangedEvent) Apply(run flows.FlowRun) error {
-	run.Results().Save(e.Name, e.Value, e.Category, e.CategoryLocalized, e.NodeUUID, e.Input, run.Session().Environment().Now().In(time.UTC))
+	run.Results().Save(e.Name, e.Value, e.Category, e.CategoryLocalized, e.NodeUUID, e.Input, e.Extra, run.Environment().Now().In(time.UTC))
 	return nil
 }
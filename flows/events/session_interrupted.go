@@ -0,0 +1,52 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeSessionInterrupted is the type of our session interrupted event
+const TypeSessionInterrupted string = "session_interrupted"
+
+// SessionInterruptedEvent events are sent by the caller to notify the engine that the session has been interrupted,
+// e.g. because the contact has been started in another flow. Every active or waiting run is exited with a status of
+// interrupted, and the session ends without resuming any of them.
+//
+//   {
+//     "type": "session_interrupted",
+//     "created_on": "2006-01-02T15:04:05Z"
+//   }
+//
+// @event session_interrupted
+type SessionInterruptedEvent struct {
+	BaseEvent
+	CallerOnlyEvent
+}
+
+// NewSessionInterruptedEvent creates a new session interrupted event
+func NewSessionInterruptedEvent() *SessionInterruptedEvent {
+	return &SessionInterruptedEvent{BaseEvent: NewBaseEvent()}
+}
+
+// Type returns the type of this event
+func (e *SessionInterruptedEvent) Type() string { return TypeSessionInterrupted }
+
+// Validate validates our event is valid and has all the assets it needs
+func (e *SessionInterruptedEvent) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// Apply applies this event to the given run
+func (e *SessionInterruptedEvent) Apply(run flows.FlowRun) error {
+	if run.Status() != flows.RunStatusWaiting {
+		return fmt.Errorf("can only be applied to waiting runs")
+	}
+
+	for _, r := range run.Session().Runs() {
+		if r.Status() == flows.RunStatusActive || r.Status() == flows.RunStatusWaiting {
+			r.Exit(flows.RunStatusInterrupted)
+		}
+	}
+	return nil
+}
@@ -40,8 +40,8 @@ const TypeSessionTriggered string = "session_triggered"
 //
 // @event session_triggered
 type SessionTriggeredEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	Flow          *flows.FlowReference      `json:"flow" validate:"required"`
 	URNs          []urns.URN                `json:"urns,omitempty" validate:"dive,urn"`
@@ -54,7 +54,7 @@ type SessionTriggeredEvent struct {
 // NewSessionTriggeredEvent returns a new session triggered event
 func NewSessionTriggeredEvent(flow *flows.FlowReference, urns []urns.URN, contacts []*flows.ContactReference, groups []*flows.GroupReference, createContact bool, runSnapshot json.RawMessage) *SessionTriggeredEvent {
 	return &SessionTriggeredEvent{
-		baseEvent:     newBaseEvent(),
+		BaseEvent:     NewBaseEvent(),
 		Flow:          flow,
 		URNs:          urns,
 		Contacts:      contacts,
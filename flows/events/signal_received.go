@@ -0,0 +1,69 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeSignalReceived is the type of our signal received event
+const TypeSignalReceived string = "signal_received"
+
+// SignalReceivedEvent events are sent by the caller to resume a flow which is waiting for an out-of-band signal. The
+// signal name must match the one the flow is waiting for, otherwise the event is ignored, and the optional payload is
+// made available in expressions as `@run.signal.payload`.
+//
+//   {
+//     "type": "signal_received",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "signal": "payment_confirmed",
+//     "payload": {"status": "paid", "amount": 25}
+//   }
+//
+// @event signal_received
+type SignalReceivedEvent struct {
+	BaseEvent
+	CallerOnlyEvent
+
+	Signal  string          `json:"signal" validate:"required"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewSignalReceivedEvent creates a new signal received event
+func NewSignalReceivedEvent(signal string, payload json.RawMessage) *SignalReceivedEvent {
+	return &SignalReceivedEvent{
+		BaseEvent: NewBaseEvent(),
+		Signal:    signal,
+		Payload:   payload,
+	}
+}
+
+// Type returns the type of this event
+func (e *SignalReceivedEvent) Type() string { return TypeSignalReceived }
+
+// Validate validates our event is valid and has all the assets it needs
+func (e *SignalReceivedEvent) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// a wait which waits for a named signal
+type signalWait interface {
+	WaitsFor(signal string) bool
+}
+
+// Apply applies this event to the given run. Signals which the session isn't waiting for are ignored.
+func (e *SignalReceivedEvent) Apply(run flows.FlowRun) error {
+	if run.Status() != flows.RunStatusWaiting {
+		return fmt.Errorf("can only be applied to waiting runs")
+	}
+
+	wait, isSignalWait := run.Session().Wait().(signalWait)
+	if !isSignalWait || !wait.WaitsFor(e.Signal) {
+		return nil
+	}
+
+	run.SetSignal(flows.NewSignal(e.Signal, e.Payload, e.CreatedOn()))
+	run.ResetExpiration(nil)
+	return nil
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeSignalWait is the type of our signal wait event
+const TypeSignalWait string = "signal_wait"
+
+// SignalWaitEvent events are created when a flow pauses waiting for an out-of-band signal, e.g. a payment
+// confirmation. The caller should resume the flow with a [signal_received](#event:signal_received) event with the
+// same signal name. If a timeout is set, then the caller should resume the flow with a
+// [wait_timed_out](#event:wait_timed_out) event if the signal hasn't been received by that time.
+//
+//   {
+//     "type": "signal_wait",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "signal": "payment_confirmed",
+//     "timeout_on": "2006-01-02T16:04:05Z"
+//   }
+//
+// @event signal_wait
+type SignalWaitEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	Signal    string     `json:"signal" validate:"required"`
+	TimeoutOn *time.Time `json:"timeout_on,omitempty"`
+}
+
+// NewSignalWait returns a new signal wait event for the given signal and timeout
+func NewSignalWait(signal string, timeoutOn *time.Time) *SignalWaitEvent {
+	return &SignalWaitEvent{
+		BaseEvent: NewBaseEvent(),
+		Signal:    signal,
+		TimeoutOn: timeoutOn,
+	}
+}
+
+// Type returns the type of this event
+func (e *SignalWaitEvent) Type() string { return TypeSignalWait }
+
+// Apply applies this event to the given run
+func (e *SignalWaitEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
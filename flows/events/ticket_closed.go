@@ -0,0 +1,66 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeTicketClosed is the type of our ticket closed event
+const TypeTicketClosed string = "ticket_closed"
+
+// TicketClosedEvent events are sent by the caller when a human agent has closed a ticket which was opened by a run,
+// to resume that run if it is waiting for the ticket to be closed. The assignee and resolution note are made
+// available in expressions as `@run.ticket.assignee` and `@run.ticket.resolution_note`.
+//
+//   {
+//     "type": "ticket_closed",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "ticket_uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//     "assignee": "Bob",
+//     "resolution_note": "Cookies are on their way"
+//   }
+//
+// @event ticket_closed
+type TicketClosedEvent struct {
+	BaseEvent
+	CallerOnlyEvent
+
+	TicketUUID     flows.TicketUUID `json:"ticket_uuid" validate:"required,uuid4"`
+	Assignee       string           `json:"assignee,omitempty"`
+	ResolutionNote string           `json:"resolution_note,omitempty"`
+}
+
+// NewTicketClosedEvent creates a new ticket closed event
+func NewTicketClosedEvent(ticketUUID flows.TicketUUID, assignee string, resolutionNote string) *TicketClosedEvent {
+	return &TicketClosedEvent{
+		BaseEvent:      NewBaseEvent(),
+		TicketUUID:     ticketUUID,
+		Assignee:       assignee,
+		ResolutionNote: resolutionNote,
+	}
+}
+
+// Type returns the type of this event
+func (e *TicketClosedEvent) Type() string { return TypeTicketClosed }
+
+// Validate validates our event is valid and has all the assets it needs
+func (e *TicketClosedEvent) Validate(assets flows.SessionAssets) error {
+	return nil
+}
+
+// Apply applies this event to the given run
+func (e *TicketClosedEvent) Apply(run flows.FlowRun) error {
+	if run.Status() != flows.RunStatusWaiting {
+		return fmt.Errorf("can only be applied to waiting runs")
+	}
+
+	ticket := run.Ticket()
+	if ticket == nil || ticket.UUID != e.TicketUUID {
+		return fmt.Errorf("run has no ticket with uuid '%s'", e.TicketUUID)
+	}
+
+	ticket.Close(e.Assignee, e.ResolutionNote)
+	run.ResetExpiration(nil)
+	return nil
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeTicketOpened is the type of our ticket opened event
+const TypeTicketOpened string = "ticket_opened"
+
+// TicketOpenedEvent events are created when a ticket is opened in a ticketer so that a human agent can take over
+// the conversation with the contact. The caller should open the ticket in the referenced ticketer.
+//
+//   {
+//     "type": "ticket_opened",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "ticket": {
+//       "uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//       "ticketer": {"uuid": "d605bb96-258d-4097-ad0a-080937db2212", "name": "Support Tickets"},
+//       "subject": "Need help",
+//       "body": "Where are my cookies?",
+//       "status": "open"
+//     }
+//   }
+//
+// @event ticket_opened
+type TicketOpenedEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	Ticket *flows.Ticket `json:"ticket" validate:"required"`
+}
+
+// NewTicketOpenedEvent returns a new ticket opened event for the given ticket
+func NewTicketOpenedEvent(ticket *flows.Ticket) *TicketOpenedEvent {
+	return &TicketOpenedEvent{
+		BaseEvent: NewBaseEvent(),
+		Ticket:    ticket,
+	}
+}
+
+// Type returns the type of this event
+func (e *TicketOpenedEvent) Type() string { return TypeTicketOpened }
+
+// Apply applies this event to the given run
+func (e *TicketOpenedEvent) Apply(run flows.FlowRun) error {
+	// the run gets its own copy of the ticket as it will be updated when the ticket is closed
+	ticket := *e.Ticket
+	run.SetTicket(&ticket)
+	return nil
+}
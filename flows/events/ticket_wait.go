@@ -0,0 +1,48 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeTicketWait is the type of our ticket wait event
+const TypeTicketWait string = "ticket_wait"
+
+// TicketWaitEvent events are created when a flow pauses waiting for the ticket it opened to be closed by a human
+// agent. The caller should resume the flow with a [ticket_closed](#event:ticket_closed) event when that happens. If
+// a timeout is set, then the caller should resume the flow with a [wait_timed_out](#event:wait_timed_out) event if
+// the ticket hasn't been closed by that time.
+//
+//   {
+//     "type": "ticket_wait",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "ticket_uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//     "timeout_on": "2006-01-02T16:04:05Z"
+//   }
+//
+// @event ticket_wait
+type TicketWaitEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	TicketUUID flows.TicketUUID `json:"ticket_uuid,omitempty"`
+	TimeoutOn  *time.Time       `json:"timeout_on,omitempty"`
+}
+
+// NewTicketWait returns a new ticket wait event for the given ticket and timeout
+func NewTicketWait(ticketUUID flows.TicketUUID, timeoutOn *time.Time) *TicketWaitEvent {
+	return &TicketWaitEvent{
+		BaseEvent:  NewBaseEvent(),
+		TicketUUID: ticketUUID,
+		TimeoutOn:  timeoutOn,
+	}
+}
+
+// Type returns the type of this event
+func (e *TicketWaitEvent) Type() string { return TypeTicketWait }
+
+// Apply applies this event to the given run
+func (e *TicketWaitEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
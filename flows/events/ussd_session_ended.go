@@ -0,0 +1,48 @@
+package events
+
+import (
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeUSSDSessionEnded is the type of our USSD session ended event
+const TypeUSSDSessionEnded string = "ussd_session_ended"
+
+// USSDSessionEndedEvent events are created when a session which sent messages to a USSD channel ends, so that the
+// caller can close the USSD session when sending the final message, which is identified by `msg_uuid`.
+//
+//   {
+//     "type": "ussd_session_ended",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "msg_uuid": "2d611e17-fb22-457f-b802-b8f7ec5cda5b",
+//     "urn": "tel:+12065551212",
+//     "channel": {"uuid": "61602f3e-f603-4c70-8a8f-c477505bf4bf", "name": "Africa's Talking"}
+//   }
+//
+// @event ussd_session_ended
+type USSDSessionEndedEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	MsgUUID flows.MsgUUID           `json:"msg_uuid" validate:"required,uuid4"`
+	URN     urns.URN                `json:"urn"      validate:"omitempty,urn"`
+	Channel *flows.ChannelReference `json:"channel"  validate:"required"`
+}
+
+// NewUSSDSessionEndedEvent returns a new USSD session ended event for the given final message
+func NewUSSDSessionEndedEvent(msg *flows.MsgOut) *USSDSessionEndedEvent {
+	return &USSDSessionEndedEvent{
+		BaseEvent: NewBaseEvent(),
+		MsgUUID:   msg.UUID(),
+		URN:       msg.URN(),
+		Channel:   msg.Channel(),
+	}
+}
+
+// Type returns the type of this event
+func (e *USSDSessionEndedEvent) Type() string { return TypeUSSDSessionEnded }
+
+// Apply applies this event to the given run
+func (e *USSDSessionEndedEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/waits/hints"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// TypeVoiceWait is the type of our voice wait event
+const TypeVoiceWait string = "voice_wait"
+
+// VoiceWaitEvent events are created when a voice flow pauses waiting for the contact to respond on the current
+// phone call. The hint describes what should be collected, i.e. `digits` entered by DTMF or an `audio` recording,
+// and the caller should resume the flow with a [msg_received](#event:msg_received) event containing the digits as
+// text or the recording as an attachment. If a timeout is set, then the caller should resume the flow with a
+// [wait_timed_out](#event:wait_timed_out) event if nothing was collected by that time.
+//
+//   {
+//     "type": "voice_wait",
+//     "created_on": "2006-01-02T15:04:05Z",
+//     "timeout_on": "2006-01-02T15:05:05Z",
+//     "hint": {
+//       "type": "digits",
+//       "terminated_by": "#"
+//     }
+//   }
+//
+// @event voice_wait
+type VoiceWaitEvent struct {
+	BaseEvent
+	EngineOnlyEvent
+
+	TimeoutOn *time.Time `json:"timeout_on,omitempty"`
+	Hint      flows.Hint `json:"hint" validate:"required"`
+}
+
+// NewVoiceWait returns a new voice wait with the passed in timeout and hint
+func NewVoiceWait(timeoutOn *time.Time, hint flows.Hint) *VoiceWaitEvent {
+	return &VoiceWaitEvent{
+		BaseEvent: NewBaseEvent(),
+		TimeoutOn: timeoutOn,
+		Hint:      hint,
+	}
+}
+
+// Type returns the type of this event
+func (e *VoiceWaitEvent) Type() string { return TypeVoiceWait }
+
+// Apply applies this event to the given run
+func (e *VoiceWaitEvent) Apply(run flows.FlowRun) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type voiceWaitEventEnvelope struct {
+	BaseEvent
+
+	TimeoutOn *time.Time           `json:"timeout_on,omitempty"`
+	Hint      *utils.TypedEnvelope `json:"hint"`
+}
+
+// UnmarshalJSON unmarshals a voice wait event from the given JSON
+func (e *VoiceWaitEvent) UnmarshalJSON(data []byte) error {
+	envelope := &voiceWaitEventEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return err
+	}
+
+	e.BaseEvent = envelope.BaseEvent
+	e.TimeoutOn = envelope.TimeoutOn
+
+	var err error
+	if envelope.Hint != nil {
+		if e.Hint, err = hints.HintFromEnvelope(envelope.Hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
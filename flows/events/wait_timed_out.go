@@ -19,13 +19,13 @@ const TypeWaitTimedOut string = "wait_timed_out"
 //
 // @event wait_timed_out
 type WaitTimedOutEvent struct {
-	baseEvent
-	callerOnlyEvent
+	BaseEvent
+	CallerOnlyEvent
 }
 
 // NewWaitTimedOutEvent creates a new wait timed out event
 func NewWaitTimedOutEvent() *WaitTimedOutEvent {
-	return &WaitTimedOutEvent{baseEvent: newBaseEvent()}
+	return &WaitTimedOutEvent{BaseEvent: NewBaseEvent()}
 }
 
 // Type returns the type of this event
@@ -44,7 +44,7 @@ func (e *WaitTimedOutEvent) Apply(run flows.FlowRun) error {
 		return fmt.Errorf("can only be applied to waiting runs")
 	}
 
-	if wait.Timeout() == nil || wait.TimeoutOn() == nil {
+	if wait.TimeoutOn() == nil {
 		return fmt.Errorf("can only be applied when session wait has timeout")
 	}
 
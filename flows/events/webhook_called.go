@@ -9,7 +9,10 @@ const TypeWebhookCalled string = "webhook_called"
 
 // WebhookCalledEvent events are created when a webhook is called. The event contains
 // the status and status code of the response, as well as a full dump of the
-// request and response.
+// request and response. If the webhook was called for a subscriber of a resthook,
+// the event also contains the slug of that resthook, and a status code of 410
+// means the caller should unsubscribe that URL from the resthook. Webhooks which are
+// retried create an event for each attempt.
 //
 //   {
 //     "type": "webhook_called",
@@ -18,30 +21,35 @@ const TypeWebhookCalled string = "webhook_called"
 //     "status": "success",
 //     "status_code": 200,
 //     "request": "GET https://api.ipify.org?format=json",
-//     "response": "HTTP/1.1 200 OK {\"ip\":\"190.154.48.130\"}"
+//     "response": "HTTP/1.1 200 OK {\"ip\":\"190.154.48.130\"}",
+//     "attempt": 1
 //   }
 //
 // @event webhook_called
 type WebhookCalledEvent struct {
-	baseEvent
-	engineOnlyEvent
+	BaseEvent
+	EngineOnlyEvent
 
 	URL        string              `json:"url"         validate:"required"`
+	Resthook   string              `json:"resthook,omitempty"`
 	Status     flows.WebhookStatus `json:"status"      validate:"required"`
 	StatusCode int                 `json:"status_code"`
 	Request    string              `json:"request"     validate:"required"`
 	Response   string              `json:"response"`
+	Attempt    int                 `json:"attempt,omitempty"`
 }
 
-// NewWebhookCalledEvent returns a new webhook called event
-func NewWebhookCalledEvent(url string, status flows.WebhookStatus, statusCode int, request string, response string) *WebhookCalledEvent {
+// NewWebhookCalledEvent returns a new webhook called event for the given call, which may have been made for a resthook
+func NewWebhookCalledEvent(webhook *flows.WebhookCall, resthook string) *WebhookCalledEvent {
 	return &WebhookCalledEvent{
-		baseEvent:  newBaseEvent(),
-		URL:        url,
-		Status:     status,
-		StatusCode: statusCode,
-		Request:    request,
-		Response:   response,
+		BaseEvent:  NewBaseEvent(),
+		URL:        webhook.URL(),
+		Resthook:   resthook,
+		Status:     webhook.Status(),
+		StatusCode: webhook.StatusCode(),
+		Request:    webhook.Request(),
+		Response:   webhook.Response(),
+		Attempt:    webhook.Attempts(),
 	}
 }
 
@@ -35,6 +35,12 @@ func NewMsgInput(uuid flows.InputUUID, channel flows.Channel, createdOn time.Tim
 // Type returns the type of this event
 func (i *MsgInput) Type() string { return TypeMsg }
 
+// Text returns the text of the message
+func (i *MsgInput) Text() string { return i.text }
+
+// Attachments returns the attachments of the message
+func (i *MsgInput) Attachments() flows.AttachmentList { return i.attachments }
+
 // Resolve resolves the given key when this input is referenced in an expression
 func (i *MsgInput) Resolve(env utils.Environment, key string) types.XValue {
 	switch key {
@@ -1,6 +1,8 @@
 package flows
 
 import (
+	"context"
+	"math/rand"
 	"time"
 
 	"github.com/nyaruka/goflow/excellent/types"
@@ -22,6 +24,18 @@ type FlowUUID utils.UUID
 
 func (u FlowUUID) String() string { return string(u) }
 
+// FlowType is the type of a flow, which determines which actions it can contain
+type FlowType string
+
+// different types of flows
+const (
+	FlowTypeMessaging FlowType = "messaging"
+	FlowTypeVoice     FlowType = "voice"
+)
+
+// AllFlowTypes is all the flow types
+var AllFlowTypes = []FlowType{FlowTypeMessaging, FlowTypeVoice}
+
 // ActionUUID is the UUID of an action
 type ActionUUID utils.UUID
 
@@ -57,6 +71,21 @@ type GroupUUID utils.UUID
 
 func (u GroupUUID) String() string { return string(u) }
 
+// ClassifierUUID is the UUID of an NLU classifier
+type ClassifierUUID utils.UUID
+
+func (u ClassifierUUID) String() string { return string(u) }
+
+// TicketerUUID is the UUID of a ticketer
+type TicketerUUID utils.UUID
+
+func (u TicketerUUID) String() string { return string(u) }
+
+// TicketUUID is the UUID of a ticket
+type TicketUUID utils.UUID
+
+func (u TicketUUID) String() string { return string(u) }
+
 // InputUUID is the UUID of an input
 type InputUUID utils.UUID
 
@@ -82,6 +111,12 @@ const (
 
 	// SessionStatusErrored represents a session that encountered an error
 	SessionStatusErrored SessionStatus = "errored"
+
+	// SessionStatusExpired represents a session whose root run expired
+	SessionStatusExpired SessionStatus = "expired"
+
+	// SessionStatusInterrupted represents a session that was interrupted before it could complete
+	SessionStatusInterrupted SessionStatus = "interrupted"
 )
 
 func (r SessionStatus) String() string { return string(r) }
@@ -116,6 +151,9 @@ type SessionAssets interface {
 	GetChannel(ChannelUUID) (Channel, error)
 	GetChannelSet() (*ChannelSet, error)
 
+	GetClassifier(ClassifierUUID) (Classifier, error)
+	GetClassifierSet() (*ClassifierSet, error)
+
 	GetField(string) (*Field, error)
 	GetFieldSet() (*FieldSet, error)
 
@@ -127,6 +165,15 @@ type SessionAssets interface {
 	GetLabel(LabelUUID) (*Label, error)
 	GetLabelSet() (*LabelSet, error)
 
+	GetResthook(string) (*Resthook, error)
+	GetResthookSet() (*ResthookSet, error)
+
+	GetSecret(string) (*Secret, error)
+	GetSecretSet() (*SecretSet, error)
+
+	GetTicketer(TicketerUUID) (*Ticketer, error)
+	GetTicketerSet() (*TicketerSet, error)
+
 	HasLocations() bool
 	GetLocationHierarchy() (*utils.LocationHierarchy, error)
 }
@@ -152,6 +199,7 @@ type Flow interface {
 
 	UUID() FlowUUID
 	Name() string
+	Type() FlowType
 	Revision() int
 	Language() utils.Language
 	ExpireAfterMinutes() int
@@ -179,6 +227,7 @@ type Node interface {
 // Action is an action within a flow node
 type Action interface {
 	UUID() ActionUUID
+	AllowedFlowTypes() []FlowType
 
 	Execute(FlowRun, Step, EventLog) error
 	Validate(SessionAssets) error
@@ -217,11 +266,17 @@ type Wait interface {
 
 	Timeout() *int
 	TimeoutOn() *time.Time
+	AllowedFlowTypes() []FlowType
 
-	Begin(FlowRun, Step)
+	Begin(FlowRun, Step) error
 	CanResume([]Event) bool
 }
 
+// Hint tells the caller what kind of input a wait is expecting, e.g. so that channels can show a picker for it
+type Hint interface {
+	utils.Typed
+}
+
 // Localization provide a way to get the translations for a specific language
 type Localization interface {
 	AddItemTranslation(utils.Language, utils.UUID, string, []string)
@@ -289,6 +344,21 @@ type Event interface {
 	utils.Typed
 }
 
+// EventListener is something which is notified of each event as it is applied to a run in a session, along with the
+// step and action (if any) which produced it. Returning an error vetoes the event, which won't be applied, and aborts
+// execution of the session.
+type EventListener interface {
+	OnEvent(FlowRun, Step, Action, Event) error
+}
+
+// EventListenerFunc allows a plain function to be used as an event listener
+type EventListenerFunc func(FlowRun, Step, Action, Event) error
+
+// OnEvent calls this function with the given event
+func (f EventListenerFunc) OnEvent(run FlowRun, step Step, action Action, event Event) error {
+	return f(run, step, action, event)
+}
+
 // EventLog is the log of events the caller must apply after each call
 type EventLog interface {
 	Add(Event)
@@ -338,13 +408,21 @@ type Step interface {
 	ArrivedOn() time.Time
 	LeftOn() *time.Time
 
-	Leave(ExitUUID)
+	Leave(ExitUUID, time.Time)
 }
 
 type EngineConfig interface {
 	DisableWebhooks() bool
 	WebhookMocks() []*WebhookMock
 	MaxWebhookResponseBytes() int
+	MaxStepsPerCall() int
+	MaxRunsPerSession() int
+	MaxSubflowDepth() int
+	MaxNodeVisitsPerCall() int
+	AllowLoopsWithExternalCalls() bool
+	MaxUSSDMsgLength() int
+	WebhookRetryBackoff() time.Duration
+	Clock() utils.Clock
 }
 
 // Session represents the session of a flow run which may contain many runs
@@ -353,6 +431,9 @@ type Session interface {
 
 	Environment() utils.Environment
 	SetEnvironment(utils.Environment)
+	Clock() utils.Clock
+	Rand() *rand.Rand
+	NewUUID() utils.UUID
 
 	Contact() *Contact
 	SetContact(*Contact)
@@ -364,7 +445,11 @@ type Session interface {
 	FlowOnStack(FlowUUID) bool
 
 	Start(Trigger, []Event) error
+	StartContext(context.Context, Trigger, []Event) error
 	Resume([]Event) error
+	ResumeContext(context.Context, []Event) error
+	Context() context.Context
+	Interrupt() error
 	Runs() []FlowRun
 	GetRun(RunUUID) (FlowRun, error)
 	GetCurrentChild(FlowRun) FlowRun
@@ -372,9 +457,15 @@ type Session interface {
 
 	Events() []Event
 	LogEvent(Event)
+	AddEventListener(EventListener)
+	EventListeners() []EventListener
 
 	EngineConfig() EngineConfig
 	HTTPClient() *utils.HTTPClient
+	ClassificationService() ClassificationService
+	SetClassificationService(ClassificationService)
+	AirtimeService() AirtimeService
+	SetAirtimeService(AirtimeService)
 }
 
 // RunSummary represents the minimum information available about all runs (current or related) and is the
@@ -406,6 +497,9 @@ type RunEnvironment interface {
 //  * `results` the results that have been saved for this run
 //  * `results.[snaked_result_name]` the value of the specific result, e.g. `run.results.age`
 //  * `webhook` the last [webhook](#context:webhook) call made in the current run
+//  * `signal` the last signal received by the current run, e.g. `run.signal.payload`
+//  * `ticket` the last ticket opened by the current run, e.g. `run.ticket.status`
+//  * `airtime` the last airtime transfer made by the current run, e.g. `run.airtime.actual_amount`
 //
 // Examples:
 //
@@ -422,15 +516,21 @@ type FlowRun interface {
 	Context() types.XValue
 	Input() Input
 	Webhook() *WebhookCall
+	Signal() *Signal
+	Ticket() *Ticket
+	AirtimeTransfer() *AirtimeTransfer
 
 	SetContact(*Contact)
 	SetInput(Input)
 	SetStatus(RunStatus)
 	SetWebhook(*WebhookCall)
+	SetSignal(*Signal)
+	SetTicket(*Ticket)
+	SetAirtimeTransfer(*AirtimeTransfer)
 
 	ApplyEvent(Step, Action, Event) error
-	AddError(Step, Action, error)
-	AddFatalError(Step, Action, error)
+	AddError(Step, Action, error) error
+	AddFatalError(Step, Action, error) error
 
 	CreateStep(Node) Step
 	Path() []Step
@@ -2,7 +2,6 @@ package flows
 
 import (
 	"github.com/nyaruka/gocommon/urns"
-	"github.com/nyaruka/goflow/utils"
 )
 
 // BaseMsg represents a incoming or outgoing message with the session contact
@@ -44,7 +43,7 @@ func NewMsgIn(uuid MsgUUID, urn urns.URN, channel Channel, text string, attachme
 }
 
 // NewMsgOut creates a new outgoing message
-func NewMsgOut(urn urns.URN, channel Channel, text string, attachments []Attachment, quickReplies []string) *MsgOut {
+func NewMsgOut(uuid MsgUUID, urn urns.URN, channel Channel, text string, attachments []Attachment, quickReplies []string) *MsgOut {
 	var channelRef *ChannelReference
 	if channel != nil {
 		channelRef = channel.Reference()
@@ -52,7 +51,7 @@ func NewMsgOut(urn urns.URN, channel Channel, text string, attachments []Attachm
 
 	return &MsgOut{
 		BaseMsg: BaseMsg{
-			UUID_:        MsgUUID(utils.NewUUID()),
+			UUID_:        uuid,
 			URN_:         urn,
 			Channel_:     channelRef,
 			Text_:        text,
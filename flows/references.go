@@ -22,6 +22,17 @@ func NewChannelReference(uuid ChannelUUID, name string) *ChannelReference {
 	return &ChannelReference{UUID: uuid, Name: name}
 }
 
+// ClassifierReference is used to reference an NLU classifier
+type ClassifierReference struct {
+	UUID ClassifierUUID `json:"uuid" validate:"required,uuid"`
+	Name string         `json:"name"`
+}
+
+// NewClassifierReference creates a new classifier reference with the given UUID and name
+func NewClassifierReference(uuid ClassifierUUID, name string) *ClassifierReference {
+	return &ClassifierReference{UUID: uuid, Name: name}
+}
+
 // ContactReference is used to reference a contact
 type ContactReference struct {
 	UUID ContactUUID `json:"uuid" validate:"required,uuid4"`
@@ -89,6 +100,17 @@ func NewVariableLabelReference(nameMatch string) *LabelReference {
 	return &LabelReference{NameMatch: nameMatch}
 }
 
+// TicketerReference is used to reference a ticketer
+type TicketerReference struct {
+	UUID TicketerUUID `json:"uuid" validate:"required,uuid"`
+	Name string       `json:"name"`
+}
+
+// NewTicketerReference creates a new ticketer reference with the given UUID and name
+func NewTicketerReference(uuid TicketerUUID, name string) *TicketerReference {
+	return &TicketerReference{UUID: uuid, Name: name}
+}
+
 //------------------------------------------------------------------------------------------
 // Validation
 //------------------------------------------------------------------------------------------
@@ -0,0 +1,80 @@
+package flows
+
+import (
+	"encoding/json"
+
+	"github.com/nyaruka/goflow/utils"
+)
+
+// Resthook is a set of URLs which are subscribed to the named event, identified by its slug
+type Resthook struct {
+	slug        string
+	subscribers []string
+}
+
+// NewResthook creates a new resthook given the passed in slug and subscriber URLs
+func NewResthook(slug string, subscribers []string) *Resthook {
+	return &Resthook{slug: slug, subscribers: subscribers}
+}
+
+// Slug returns the slug of this resthook
+func (r *Resthook) Slug() string { return r.slug }
+
+// Subscribers returns the URLs which are subscribed to this resthook
+func (r *Resthook) Subscribers() []string { return r.subscribers }
+
+// ResthookSet defines the unordered set of all resthooks for a session
+type ResthookSet struct {
+	resthooks       []*Resthook
+	resthooksBySlug map[string]*Resthook
+}
+
+// NewResthookSet creates a new resthook set from the given slice of resthooks
+func NewResthookSet(resthooks []*Resthook) *ResthookSet {
+	s := &ResthookSet{resthooks: resthooks, resthooksBySlug: make(map[string]*Resthook, len(resthooks))}
+	for _, resthook := range s.resthooks {
+		s.resthooksBySlug[resthook.slug] = resthook
+	}
+	return s
+}
+
+// FindBySlug finds the resthook with the given slug
+func (s *ResthookSet) FindBySlug(slug string) *Resthook {
+	return s.resthooksBySlug[slug]
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type resthookEnvelope struct {
+	Slug        string   `json:"slug" validate:"required"`
+	Subscribers []string `json:"subscribers" validate:"dive,url"`
+}
+
+// ReadResthook reads a resthook from the given JSON
+func ReadResthook(data json.RawMessage) (*Resthook, error) {
+	var re resthookEnvelope
+	if err := utils.UnmarshalAndValidate(data, &re, "resthook"); err != nil {
+		return nil, err
+	}
+
+	return NewResthook(re.Slug, re.Subscribers), nil
+}
+
+// ReadResthookSet reads a resthook set from the given JSON
+func ReadResthookSet(data json.RawMessage) (*ResthookSet, error) {
+	items, err := utils.UnmarshalArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	resthooks := make([]*Resthook, len(items))
+	for r := range items {
+		if resthooks[r], err = ReadResthook(items[r]); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewResthookSet(resthooks), nil
+}
@@ -1,6 +1,7 @@
 package flows
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/nyaruka/goflow/excellent/types"
@@ -15,6 +16,7 @@ import (
 //  * `category` the category of the result
 //  * `category_localized` the localized category of the result
 //  * `input` the input associated with the result
+//  * `extra` any extra data associated with the result, e.g. the intents of a [classification](#action:call_classifier)
 //  * `node_uuid` the UUID of the node where the result was created
 //  * `created_on` the time when the result was created
 //
@@ -26,13 +28,14 @@ import (
 //
 // @context result
 type Result struct {
-	Name              string    `json:"name"`
-	Value             string    `json:"value"`
-	Category          string    `json:"category,omitempty"`
-	CategoryLocalized string    `json:"category_localized,omitempty"`
-	NodeUUID          NodeUUID  `json:"node_uuid"`
-	Input             *string   `json:"input,omitempty"`
-	CreatedOn         time.Time `json:"created_on"`
+	Name              string          `json:"name"`
+	Value             string          `json:"value"`
+	Category          string          `json:"category,omitempty"`
+	CategoryLocalized string          `json:"category_localized,omitempty"`
+	NodeUUID          NodeUUID        `json:"node_uuid"`
+	Input             *string         `json:"input,omitempty"`
+	Extra             json.RawMessage `json:"extra,omitempty"`
+	CreatedOn         time.Time       `json:"created_on"`
 }
 
 // Resolve resolves the passed in key to a value. Result values have a name, value, category, node and created_on
@@ -54,6 +57,11 @@ func (r *Result) Resolve(env utils.Environment, key string) types.XValue {
 			return types.NewXText(*r.Input)
 		}
 		return nil
+	case "extra":
+		if r.Extra != nil {
+			return types.JSONToXValue(r.Extra)
+		}
+		return nil
 	case "node_uuid":
 		return types.NewXText(string(r.NodeUUID))
 	case "created_on":
@@ -97,7 +105,7 @@ func (r Results) Clone() Results {
 }
 
 // Save saves a new result in our map. The key is saved in a snakified format
-func (r Results) Save(name string, value string, category string, categoryLocalized string, nodeUUID NodeUUID, input *string, createdOn time.Time) {
+func (r Results) Save(name string, value string, category string, categoryLocalized string, nodeUUID NodeUUID, input *string, extra json.RawMessage, createdOn time.Time) {
 	r[utils.Snakify(name)] = &Result{
 		Name:              name,
 		Value:             value,
@@ -105,6 +113,7 @@ func (r Results) Save(name string, value string, category string, categoryLocali
 		CategoryLocalized: categoryLocalized,
 		NodeUUID:          nodeUUID,
 		Input:             input,
+		Extra:             extra,
 		CreatedOn:         createdOn,
 	}
 }
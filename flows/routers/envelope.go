@@ -7,22 +7,28 @@ import (
 	"github.com/nyaruka/goflow/utils"
 )
 
+var registeredTypes = map[string](func() flows.Router){}
+
+// RegisterType registers a new type of router so that it can be read from flow definitions. The given function
+// should return a new empty instance of the router type which is then populated from JSON.
+func RegisterType(name string, initFunc func() flows.Router) {
+	registeredTypes[name] = initFunc
+}
+
+func init() {
+	RegisterType(TypeFirst, func() flows.Router { return &FirstRouter{} })
+	RegisterType(TypeSwitch, func() flows.Router { return &SwitchRouter{} })
+	RegisterType(TypeRandom, func() flows.Router { return &RandomRouter{} })
+	RegisterType(TypeRandomOnce, func() flows.Router { return &RandomOnceRouter{} })
+}
+
 // RouterFromEnvelope attempts to build a router given the passed in TypedEnvelope
 func RouterFromEnvelope(envelope *utils.TypedEnvelope) (flows.Router, error) {
-	var router flows.Router
-
-	switch envelope.Type {
-	case TypeFirst:
-		router = &FirstRouter{}
-	case TypeSwitch:
-		router = &SwitchRouter{}
-	case TypeRandom:
-		router = &RandomRouter{}
-	case TypeRandomOnce:
-		router = &RandomOnceRouter{}
-	default:
+	initFunc := registeredTypes[envelope.Type]
+	if initFunc == nil {
 		return nil, fmt.Errorf("Unknown router type: %s", envelope.Type)
 	}
 
+	router := initFunc()
 	return router, utils.UnmarshalAndValidate(envelope.Data, router, fmt.Sprintf("router[type=%s]", envelope.Type))
 }
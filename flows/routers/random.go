@@ -34,6 +34,6 @@ func (r *RandomRouter) PickRoute(run flows.FlowRun, exits []flows.Exit, step flo
 	}
 
 	// pick a random exit
-	exitN := utils.RandIntN(len(exits))
+	exitN := run.Environment().Rand().Intn(len(exits))
 	return nil, flows.NewRoute(exits[exitN].UUID(), fmt.Sprintf("%d", exitN)), nil
 }
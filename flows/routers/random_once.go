@@ -76,6 +76,6 @@ func (r *RandomOnceRouter) PickRoute(run flows.FlowRun, exits []flows.Exit, step
 	}
 
 	// ok, now pick one randomly
-	exitN := utils.RandIntN(len(validExits))
-	return nil, flows.NewRoute(validExits[exitN], string(exitN)), nil
+	exitN := run.Environment().Rand().Intn(len(validExits))
+	return nil, flows.NewRoute(validExits[exitN], fmt.Sprintf("%d", exitN)), nil
 }
@@ -78,7 +78,9 @@ func (r *SwitchRouter) PickRoute(run flows.FlowRun, exits []flows.Exit, step flo
 	// first evaluate our operand
 	operand, err := run.EvaluateTemplate(r.Operand)
 	if err != nil {
-		run.AddError(step, nil, err)
+		if err := run.AddError(step, nil, err); err != nil {
+			return nil, flows.NoRoute, err
+		}
 	}
 
 	var operandAsStr *string
@@ -109,7 +111,9 @@ func (r *SwitchRouter) PickRoute(run flows.FlowRun, exits []flows.Exit, step flo
 			test := localizedArgs[i]
 			arg, err := run.EvaluateTemplate(test)
 			if err != nil {
-				run.AddError(step, nil, err)
+				if err := run.AddError(step, nil, err); err != nil {
+					return nil, flows.NoRoute, err
+				}
 			}
 			args = append(args, arg)
 		}
@@ -141,7 +145,9 @@ func (r *SwitchRouter) PickRoute(run flows.FlowRun, exits []flows.Exit, step flo
 		// evaluate our operand as a string
 		value, xerr := types.ToXText(env, operand)
 		if xerr != nil {
-			run.AddError(step, nil, xerr)
+			if err := run.AddError(step, nil, xerr); err != nil {
+				return nil, flows.NoRoute, err
+			}
 		}
 
 		return operandAsStr, flows.NewRoute(r.Default, value.Native()), nil
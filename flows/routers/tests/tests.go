@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"regexp"
 	"strings"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"github.com/nyaruka/goflow/excellent/types"
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/inputs"
+	"github.com/nyaruka/goflow/flows/waits/hints"
 	"github.com/nyaruka/goflow/utils"
 
 	"github.com/nyaruka/phonenumbers"
@@ -18,7 +21,6 @@ import (
 // TODO:
 // InterruptTest
 // TimeoutTest
-// AirtimeStatusTest
 
 //------------------------------------------------------------------------------------------
 // Mapping
@@ -37,8 +39,17 @@ var XTESTS = map[string]functions.XFunction{
 	"has_value": functions.OneArgFunction(HasValue),
 
 	"has_group":          functions.TwoArgFunction(HasGroup),
+	"has_intent":         functions.ThreeArgFunction(HasIntent),
+	"has_top_intent":     functions.ThreeArgFunction(HasTopIntent),
 	"has_webhook_status": functions.TwoArgFunction(HasWebhookStatus),
+	"has_airtime_status": functions.TwoArgFunction(HasAirtimeStatus),
 	"has_wait_timed_out": functions.OneArgFunction(HasWaitTimedOut),
+	"has_hinted_input":   functions.OneArgFunction(HasHintedInput),
+
+	"has_image":    functions.OneArgFunction(HasImage),
+	"has_audio":    functions.OneArgFunction(HasAudio),
+	"has_video":    functions.OneArgFunction(HasVideo),
+	"has_location": functions.OneArgFunction(HasLocation),
 
 	"is_text_eq":      functions.TwoTextFunction(IsTextEQ),
 	"has_phrase":      functions.TwoTextFunction(HasPhrase),
@@ -166,6 +177,89 @@ func HasWaitTimedOut(env utils.Environment, value types.XValue) types.XValue {
 	return XFalseResult
 }
 
+// HasHintedInput returns whether the last input matches the hint of the wait that received it, e.g. that it contains
+// only digits if the wait had a `digits` hint, or an image attachment if it had an `image` hint. If the wait had no
+// hint then any input with text matches.
+//
+//   @(has_hinted_input(run)) -> true
+//   @(has_hinted_input(run).match) -> Hi there
+//   @(has_hinted_input("abc")) -> ERROR
+//
+// @test has_hinted_input(run)
+func HasHintedInput(env utils.Environment, value types.XValue) types.XValue {
+	// first parameter needs to be a flow run
+	run, isRun := value.(flows.FlowRun)
+	if !isRun {
+		return types.NewXErrorf("must be called with a run as first argument")
+	}
+
+	input, isMsg := run.Input().(*inputs.MsgInput)
+	if !isMsg {
+		return XFalseResult
+	}
+
+	text := types.NewXText(input.Text())
+
+	switch typed := lastWaitHint(run).(type) {
+	case *hints.DigitsHint:
+		return testDigits(text, typed.Count, typed.TerminatedBy)
+	case *hints.DateHint:
+		return HasDate(env, text)
+	case *hints.ImageHint:
+		return HasImage(env, input.Attachments())
+	case *hints.AudioHint:
+		return HasAudio(env, input.Attachments())
+	case *hints.VideoHint:
+		return HasVideo(env, input.Attachments())
+	case *hints.LocationHint:
+		return HasLocation(env, input.Attachments())
+	default:
+		return HasText(env, text)
+	}
+}
+
+// HasImage tests whether `attachments` contains an image, and if so returns its URL as the match
+//
+//   @(has_image(run.input.attachments)) -> true
+//   @(has_image(run.input.attachments).match) -> http://s3.amazon.com/bucket/test.jpg
+//   @(has_image("abc")) -> ERROR
+//
+// @test has_image(attachments)
+func HasImage(env utils.Environment, value types.XValue) types.XValue {
+	return testAttachments(value, "image/")
+}
+
+// HasAudio tests whether `attachments` contains an audio recording, and if so returns its URL as the match
+//
+//   @(has_audio(run.input.attachments)) -> true
+//   @(has_audio(run.input.attachments).match) -> http://s3.amazon.com/bucket/test.mp3
+//   @(has_audio("abc")) -> ERROR
+//
+// @test has_audio(attachments)
+func HasAudio(env utils.Environment, value types.XValue) types.XValue {
+	return testAttachments(value, "audio/")
+}
+
+// HasVideo tests whether `attachments` contains a video, and if so returns its URL as the match
+//
+//   @(has_video(run.input.attachments)) -> false
+//   @(has_video("abc")) -> ERROR
+//
+// @test has_video(attachments)
+func HasVideo(env utils.Environment, value types.XValue) types.XValue {
+	return testAttachments(value, "video/")
+}
+
+// HasLocation tests whether `attachments` contains a location, and if so returns its coordinates as the match
+//
+//   @(has_location(run.input.attachments)) -> false
+//   @(has_location("abc")) -> ERROR
+//
+// @test has_location(attachments)
+func HasLocation(env utils.Environment, value types.XValue) types.XValue {
+	return testAttachments(value, "geo")
+}
+
 // HasWebhookStatus tests whether the passed in `webhook` call has the passed in `status`.
 //
 //   @(has_webhook_status(run.webhook, "success")) -> true
@@ -193,6 +287,34 @@ func HasWebhookStatus(env utils.Environment, arg1 types.XValue, arg2 types.XValu
 	return XFalseResult
 }
 
+// HasAirtimeStatus tests whether the passed in airtime `transfer` has the passed in `status`, which should be one
+// of `success` or `failed`. The match is the amount actually transferred.
+//
+//   @(has_airtime_status(run.airtime, "success")) -> true
+//   @(has_airtime_status(run.airtime, "success").match) -> 0.5
+//   @(has_airtime_status(run.airtime, "failed")) -> false
+//   @(has_airtime_status("abc", "success")) -> ERROR
+//
+// @test has_airtime_status(transfer, status)
+func HasAirtimeStatus(env utils.Environment, arg1 types.XValue, arg2 types.XValue) types.XValue {
+	// is the first argument an airtime transfer
+	transfer, isTransfer := arg1.(*flows.AirtimeTransfer)
+	if !isTransfer {
+		return types.NewXErrorf("must have an airtime transfer as its first argument")
+	}
+
+	status, xerr := types.ToXText(env, arg2)
+	if xerr != nil {
+		return xerr
+	}
+
+	if string(transfer.Status) == strings.ToLower(status.Native()) {
+		return XTestResult{true, types.NewXNumber(transfer.ActualAmount)}
+	}
+
+	return XFalseResult
+}
+
 // HasGroup returns whether the `contact` is part of group with the passed in UUID
 //
 //   @(has_group(contact, "b7cf0d83-f1c9-411c-96fd-c511a4cfa86d")) -> true
@@ -221,6 +343,33 @@ func HasGroup(env utils.Environment, arg1 types.XValue, arg2 types.XValue) types
 	return XFalseResult
 }
 
+// HasIntent tests whether any intent in a classification `result` has `name` and minimum `confidence`
+//
+//   @(has_intent(run.results.intent, "greeting", 0.5)) -> true
+//   @(has_intent(run.results.intent, "greeting", 0.5).match) -> greeting
+//   @(has_intent(run.results.intent, "greeting", 0.8)) -> false
+//   @(has_intent(run.results.intent, "book_flight", 0.1)) -> false
+//   @(has_intent(run.results.favorite_color, "greeting", 0.5)) -> false
+//   @(has_intent("abc", "greeting", 0.5)) -> ERROR
+//
+// @test has_intent(result, name, confidence)
+func HasIntent(env utils.Environment, result types.XValue, name types.XValue, confidence types.XValue) types.XValue {
+	return testIntent(env, result, name, confidence, false)
+}
+
+// HasTopIntent tests whether the top intent in a classification `result` has `name` and minimum `confidence`
+//
+//   @(has_top_intent(run.results.intent, "greeting", 0.5)) -> true
+//   @(has_top_intent(run.results.intent, "greeting", 0.5).match) -> greeting
+//   @(has_top_intent(run.results.intent, "greeting", 0.8)) -> false
+//   @(has_top_intent(run.results.intent, "book_hotel", 0.1)) -> false
+//   @(has_top_intent("abc", "greeting", 0.5)) -> ERROR
+//
+// @test has_top_intent(result, name, confidence)
+func HasTopIntent(env utils.Environment, result types.XValue, name types.XValue, confidence types.XValue) types.XValue {
+	return testIntent(env, result, name, confidence, true)
+}
+
 // HasPhrase tests whether `phrase` is contained in `text`
 //
 // The words in the test phrase must appear in the same order with no other words
@@ -746,6 +895,98 @@ func HasWard(env utils.Environment, args ...types.XValue) types.XValue {
 	return XFalseResult
 }
 
+//------------------------------------------------------------------------------------------
+// Classification Test Functions
+//------------------------------------------------------------------------------------------
+
+// tests whether the classification saved as the extra of a result contains the named intent with at least the given
+// confidence, optionally only considering the top intent
+func testIntent(env utils.Environment, arg1 types.XValue, arg2 types.XValue, arg3 types.XValue, onlyTop bool) types.XValue {
+	result, isResult := arg1.(*flows.Result)
+	if !isResult {
+		return types.NewXErrorf("must have a run result as its first argument")
+	}
+
+	name, xerr := types.ToXText(env, arg2)
+	if xerr != nil {
+		return xerr
+	}
+	confidence, xerr := types.ToXNumber(env, arg3)
+	if xerr != nil {
+		return xerr
+	}
+
+	// results which aren't classifications have no intents
+	classification := &flows.Classification{}
+	if result.Extra == nil || json.Unmarshal(result.Extra, classification) != nil {
+		return XFalseResult
+	}
+
+	intents := classification.Intents
+	if onlyTop && len(intents) > 1 {
+		intents = []flows.ExtractedIntent{*classification.TopIntent()}
+	}
+
+	for _, intent := range intents {
+		if strings.EqualFold(intent.Name, name.Native()) && intent.Confidence.GreaterThanOrEqual(confidence.Native()) {
+			return XTestResult{true, types.NewXText(intent.Name)}
+		}
+	}
+
+	return XFalseResult
+}
+
+//------------------------------------------------------------------------------------------
+// Hint Test Functions
+//------------------------------------------------------------------------------------------
+
+// finds the hint of the last message or voice wait in the given run
+func lastWaitHint(run flows.FlowRun) flows.Hint {
+	runEvents := run.Events()
+	for e := len(runEvents) - 1; e >= 0; e-- {
+		switch waitEvent := runEvents[e].(type) {
+		case *events.MsgWaitEvent:
+			return waitEvent.Hint
+		case *events.VoiceWaitEvent:
+			return waitEvent.Hint
+		}
+	}
+	return nil
+}
+
+// tests whether the given attachments contain one whose content type starts with the given prefix
+func testAttachments(value types.XValue, contentTypePrefix string) types.XValue {
+	attachments, isAttachments := value.(flows.AttachmentList)
+	if !isAttachments {
+		return types.NewXErrorf("must be called with attachments as first argument")
+	}
+
+	for _, attachment := range attachments {
+		if strings.HasPrefix(attachment.ContentType(), contentTypePrefix) {
+			return XTestResult{true, types.NewXText(attachment.URL())}
+		}
+	}
+
+	return XFalseResult
+}
+
+// tests whether the given text contains only digits, optionally followed by the terminator, and of the given count
+func testDigits(text types.XText, count *int, terminatedBy string) types.XValue {
+	digits := strings.TrimSpace(text.Native())
+	if terminatedBy != "" {
+		digits = strings.TrimSuffix(digits, terminatedBy)
+	}
+
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return XFalseResult
+	}
+	if count != nil && len(digits) != *count {
+		return XFalseResult
+	}
+
+	return XTestResult{true, types.NewXText(digits)}
+}
+
 //------------------------------------------------------------------------------------------
 // Text Test Functions
 //------------------------------------------------------------------------------------------
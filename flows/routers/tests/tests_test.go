@@ -1,15 +1,19 @@
 package tests_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/excellent"
 	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/routers/tests"
 	"github.com/nyaruka/goflow/utils"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +22,33 @@ var xn = types.RequireXNumberFromString
 var xi = types.NewXNumberFromInt
 var xt = types.NewXDateTime
 
+var attachments = flows.AttachmentList{
+	flows.Attachment("image/jpeg:http://s3.amazon.com/bucket/test.jpg"),
+	flows.Attachment("video/mp4:http://s3.amazon.com/bucket/test.mp4"),
+	flows.Attachment("geo:-2.90875,-79.0117"),
+}
+
+var classification = &flows.Result{
+	Name:  "Intent",
+	Value: "book_flight",
+	Extra: json.RawMessage(`{"intents":[{"name":"book_flight","confidence":0.9},{"name":"book_hotel","confidence":0.4}],"entities":{}}`),
+}
+
+// classifiers don't necessarily return intents in order of confidence
+var unorderedClassification = &flows.Result{
+	Name:  "Intent",
+	Value: "book_flight",
+	Extra: json.RawMessage(`{"intents":[{"name":"book_hotel","confidence":0.4},{"name":"book_flight","confidence":0.9}],"entities":{}}`),
+}
+
+var airtimeTransfer = &flows.AirtimeTransfer{
+	Recipient:     urns.URN("tel:+12065551212"),
+	Currency:      "RWF",
+	DesiredAmount: decimal.RequireFromString("500"),
+	ActualAmount:  decimal.RequireFromString("450"),
+	Status:        flows.AirtimeTransferStatusSuccess,
+}
+
 type testResolvable struct{}
 
 func (r *testResolvable) Resolve(env utils.Environment, key string) types.XValue {
@@ -62,6 +93,35 @@ var testTests = []struct {
 	{"has_text", []types.XValue{nil}, false, nil, false},
 	{"has_text", []types.XValue{xs("one"), xs("two")}, false, nil, true},
 
+	{"has_intent", []types.XValue{classification, xs("book_flight"), xn("0.5")}, true, xs("book_flight"), false},
+	{"has_intent", []types.XValue{classification, xs("BOOK_HOTEL"), xn("0.4")}, true, xs("book_hotel"), false},
+	{"has_intent", []types.XValue{classification, xs("book_hotel"), xn("0.5")}, false, nil, false},
+	{"has_intent", []types.XValue{classification, xs("greeting"), xn("0.1")}, false, nil, false},
+	{"has_intent", []types.XValue{&flows.Result{Value: "red"}, xs("book_flight"), xn("0.5")}, false, nil, false},
+	{"has_intent", []types.XValue{classification, xs("book_flight"), xs("high")}, false, nil, true},
+	{"has_intent", []types.XValue{xs("book_flight"), xs("book_flight"), xn("0.5")}, false, nil, true},
+
+	{"has_top_intent", []types.XValue{classification, xs("book_flight"), xn("0.9")}, true, xs("book_flight"), false},
+	{"has_top_intent", []types.XValue{classification, xs("book_hotel"), xn("0.1")}, false, nil, false},
+	{"has_top_intent", []types.XValue{unorderedClassification, xs("book_flight"), xn("0.9")}, true, xs("book_flight"), false},
+	{"has_top_intent", []types.XValue{unorderedClassification, xs("book_hotel"), xn("0.1")}, false, nil, false},
+	{"has_top_intent", []types.XValue{classification, xs("book_flight")}, false, nil, true},
+
+	{"has_airtime_status", []types.XValue{airtimeTransfer, xs("success")}, true, xi(450), false},
+	{"has_airtime_status", []types.XValue{airtimeTransfer, xs("SUCCESS")}, true, xi(450), false},
+	{"has_airtime_status", []types.XValue{airtimeTransfer, xs("failed")}, false, nil, false},
+	{"has_airtime_status", []types.XValue{xs("success"), xs("success")}, false, nil, true},
+	{"has_airtime_status", []types.XValue{airtimeTransfer}, false, nil, true},
+
+	{"has_image", []types.XValue{attachments}, true, xs("http://s3.amazon.com/bucket/test.jpg"), false},
+	{"has_image", []types.XValue{flows.AttachmentList{}}, false, nil, false},
+	{"has_image", []types.XValue{xs("image/jpeg:http://s3.amazon.com/bucket/test.jpg")}, false, nil, true},
+	{"has_audio", []types.XValue{attachments}, false, nil, false},
+	{"has_video", []types.XValue{attachments}, true, xs("http://s3.amazon.com/bucket/test.mp4"), false},
+	{"has_location", []types.XValue{attachments}, true, xs("-2.90875,-79.0117"), false},
+	{"has_location", []types.XValue{}, false, nil, true},
+	{"has_hinted_input", []types.XValue{xs("hello")}, false, nil, true},
+
 	{"has_beginning", []types.XValue{xs("hello"), xs("hell")}, true, xs("hell"), false},
 	{"has_beginning", []types.XValue{xs("  HelloThere"), xs("hello")}, true, xs("Hello"), false},
 	{"has_beginning", []types.XValue{xs("one"), xs("two"), xs("three")}, false, nil, true},
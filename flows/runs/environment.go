@@ -2,6 +2,7 @@ package runs
 
 import (
 	"fmt"
+	"math/rand"
 	"regexp"
 	"strings"
 	"time"
@@ -34,6 +35,16 @@ func (e *runEnvironment) Timezone() *time.Location {
 	return e.run.Session().Environment().Timezone()
 }
 
+// Now returns the current time according to the session clock in the timezone of this environment
+func (e *runEnvironment) Now() time.Time {
+	return e.run.Session().Clock().Now().In(e.Timezone())
+}
+
+// Rand returns the random source of the session
+func (e *runEnvironment) Rand() *rand.Rand {
+	return e.run.Session().Rand()
+}
+
 func (e *runEnvironment) Languages() utils.LanguageList {
 	// if contact language has changed, rebuild our cached language list
 	if e.run.Contact() != nil && e.cachedLanguages[0] != e.run.Contact().Language() {
@@ -25,6 +25,9 @@ type flowRun struct {
 
 	context types.XValue
 	webhook *flows.WebhookCall
+	signal  *flows.Signal
+	ticket  *flows.Ticket
+	airtime *flows.AirtimeTransfer
 	input   flows.Input
 	parent  flows.FlowRun
 
@@ -41,13 +44,13 @@ type flowRun struct {
 // NewRun initializes a new context and flow run for the passed in flow and contact
 func NewRun(session flows.Session, flow flows.Flow, contact *flows.Contact, parent flows.FlowRun) flows.FlowRun {
 	r := &flowRun{
-		uuid:      flows.RunUUID(utils.NewUUID()),
+		uuid:      flows.RunUUID(session.NewUUID()),
 		session:   session,
 		flow:      flow,
 		contact:   contact,
 		results:   flows.NewResults(),
 		status:    flows.RunStatusActive,
-		createdOn: time.Now().UTC(),
+		createdOn: session.Clock().Now().UTC(),
 	}
 
 	r.environment = newRunEnvironment(session.Environment(), r)
@@ -73,7 +76,7 @@ func (r *flowRun) Events() []flows.Event  { return r.events }
 
 func (r *flowRun) Exit(status flows.RunStatus) {
 	r.SetStatus(status)
-	now := time.Now().UTC()
+	now := r.Environment().Now().UTC()
 	r.exitedOn = &now
 }
 func (r *flowRun) Status() flows.RunStatus { return r.status }
@@ -116,12 +119,26 @@ func (r *flowRun) Input() flows.Input         { return r.input }
 func (r *flowRun) SetInput(input flows.Input) { r.input = input }
 
 func (r *flowRun) ApplyEvent(s flows.Step, action flows.Action, event flows.Event) error {
+	// events generated by the engine are timestamped by the session clock, here and nowhere else
+	if !event.FromCaller() {
+		event.SetCreatedOn(r.Session().Clock().Now().UTC())
+	}
+	if s != nil {
+		event.SetStepUUID(s.UUID())
+	}
+
+	// give any listeners the chance to see this event, and veto it, before it's applied
+	for _, listener := range r.Session().EventListeners() {
+		if err := listener.OnEvent(r, s, action, event); err != nil {
+			return fmt.Errorf("event[type=%s] vetoed by listener: %s", event.Type(), err)
+		}
+	}
+
 	if err := event.Apply(r); err != nil {
 		return fmt.Errorf("unable to apply event[type=%s]: %s", event.Type(), err)
 	}
 
 	if s != nil {
-		event.SetStepUUID(s.UUID())
 		r.events = append(r.events, event)
 	}
 
@@ -144,18 +161,18 @@ func (r *flowRun) ApplyEvent(s flows.Step, action flows.Action, event flows.Even
 	return nil
 }
 
-func (r *flowRun) AddError(step flows.Step, action flows.Action, err error) {
-	r.ApplyEvent(step, action, events.NewErrorEvent(err))
+func (r *flowRun) AddError(step flows.Step, action flows.Action, err error) error {
+	return r.ApplyEvent(step, action, events.NewErrorEvent(err))
 }
 
-func (r *flowRun) AddFatalError(step flows.Step, action flows.Action, err error) {
-	r.ApplyEvent(step, action, events.NewFatalErrorEvent(err))
+func (r *flowRun) AddFatalError(step flows.Step, action flows.Action, err error) error {
+	return r.ApplyEvent(step, action, events.NewFatalErrorEvent(err))
 }
 
 func (r *flowRun) Path() []flows.Step { return r.path }
 func (r *flowRun) CreateStep(node flows.Node) flows.Step {
-	now := time.Now().UTC()
-	step := &step{stepUUID: flows.StepUUID(utils.NewUUID()), nodeUUID: node.UUID(), arrivedOn: now}
+	now := r.Environment().Now().UTC()
+	step := &step{stepUUID: flows.StepUUID(r.session.NewUUID()), nodeUUID: node.UUID(), arrivedOn: now}
 	r.path = append(r.path, step)
 	return step
 }
@@ -179,12 +196,21 @@ func (r *flowRun) PathLocation() (flows.Step, flows.Node, error) {
 func (r *flowRun) Webhook() *flows.WebhookCall      { return r.webhook }
 func (r *flowRun) SetWebhook(rr *flows.WebhookCall) { r.webhook = rr }
 
+func (r *flowRun) Signal() *flows.Signal          { return r.signal }
+func (r *flowRun) SetSignal(signal *flows.Signal) { r.signal = signal }
+
+func (r *flowRun) Ticket() *flows.Ticket          { return r.ticket }
+func (r *flowRun) SetTicket(ticket *flows.Ticket) { r.ticket = ticket }
+
+func (r *flowRun) AirtimeTransfer() *flows.AirtimeTransfer            { return r.airtime }
+func (r *flowRun) SetAirtimeTransfer(transfer *flows.AirtimeTransfer) { r.airtime = transfer }
+
 func (r *flowRun) CreatedOn() time.Time  { return r.createdOn }
 func (r *flowRun) ExpiresOn() *time.Time { return r.expiresOn }
 func (r *flowRun) ResetExpiration(from *time.Time) {
 	if r.Flow().ExpireAfterMinutes() >= 0 {
 		if from == nil {
-			now := time.Now().UTC()
+			now := r.Environment().Now().UTC()
 			from = &now
 		}
 
@@ -260,6 +286,21 @@ func (r *flowRun) Resolve(env utils.Environment, key string) types.XValue {
 		return r.Input()
 	case "webhook":
 		return r.Webhook()
+	case "signal":
+		if r.signal != nil {
+			return r.signal
+		}
+		return nil
+	case "ticket":
+		if r.ticket != nil {
+			return r.ticket
+		}
+		return nil
+	case "airtime":
+		if r.airtime != nil {
+			return r.airtime
+		}
+		return nil
 	case "status":
 		return types.NewXText(string(r.Status()))
 	case "results":
@@ -287,7 +328,7 @@ func (r *flowRun) Reduce(env utils.Environment) types.XPrimitive {
 }
 
 func (r *flowRun) ToXJSON(env utils.Environment) types.XText {
-	return types.ResolveKeys(env, r, "uuid", "contact", "flow", "input", "webhook", "status", "results", "created_on", "exited_on").ToXJSON(env)
+	return types.ResolveKeys(env, r, "uuid", "contact", "flow", "input", "webhook", "signal", "ticket", "airtime", "status", "results", "created_on", "exited_on").ToXJSON(env)
 }
 
 func (r *flowRun) Snapshot() flows.RunSummary {
@@ -310,9 +351,12 @@ type runEnvelope struct {
 	Status     flows.RunStatus `json:"status"`
 	ParentUUID flows.RunUUID   `json:"parent_uuid,omitempty" validate:"omitempty,uuid4"`
 
-	Results flows.Results        `json:"results,omitempty" validate:"omitempty,dive"`
-	Input   *utils.TypedEnvelope `json:"input,omitempty" validate:"omitempty,dive"`
-	Webhook *flows.WebhookCall   `json:"webhook,omitempty" validate:"omitempty,dive"`
+	Results flows.Results          `json:"results,omitempty" validate:"omitempty,dive"`
+	Input   *utils.TypedEnvelope   `json:"input,omitempty" validate:"omitempty,dive"`
+	Webhook *flows.WebhookCall     `json:"webhook,omitempty" validate:"omitempty,dive"`
+	Signal  *flows.Signal          `json:"signal,omitempty"`
+	Ticket  *flows.Ticket          `json:"ticket,omitempty"`
+	Airtime *flows.AirtimeTransfer `json:"airtime,omitempty"`
 
 	CreatedOn time.Time  `json:"created_on"`
 	ExpiresOn *time.Time `json:"expires_on"`
@@ -335,6 +379,9 @@ func ReadRun(session flows.Session, data json.RawMessage) (flows.FlowRun, error)
 	r.uuid = envelope.UUID
 	r.status = envelope.Status
 	r.webhook = envelope.Webhook
+	r.signal = envelope.Signal
+	r.ticket = envelope.Ticket
+	r.airtime = envelope.Airtime
 	r.createdOn = envelope.CreatedOn
 	r.expiresOn = envelope.ExpiresOn
 	r.exitedOn = envelope.ExitedOn
@@ -397,6 +444,9 @@ func (r *flowRun) MarshalJSON() ([]byte, error) {
 	re.ExitedOn = r.exitedOn
 	re.Results = r.results
 	re.Webhook = r.webhook
+	re.Signal = r.signal
+	re.Ticket = r.ticket
+	re.Airtime = r.airtime
 
 	if r.parent != nil {
 		re.ParentUUID = r.parent.UUID()
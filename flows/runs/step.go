@@ -23,10 +23,9 @@ func (s *step) ExitUUID() flows.ExitUUID { return s.exitUUID }
 func (s *step) ArrivedOn() time.Time     { return s.arrivedOn }
 func (s *step) LeftOn() *time.Time       { return s.leftOn }
 
-func (s *step) Leave(exit flows.ExitUUID) {
-	now := time.Now().UTC()
+func (s *step) Leave(exit flows.ExitUUID, leftOn time.Time) {
 	s.exitUUID = exit
-	s.leftOn = &now
+	s.leftOn = &leftOn
 }
 
 func (s *step) Resolve(env utils.Environment, key string) types.XValue {
@@ -0,0 +1,81 @@
+package flows
+
+import (
+	"encoding/json"
+
+	"github.com/nyaruka/goflow/utils"
+)
+
+// Secret is a named value which flows can use without it ever being exposed in the context, e.g. a key used to sign
+// webhook requests
+type Secret struct {
+	name  string
+	value string
+}
+
+// NewSecret creates a new secret given the passed in name and value
+func NewSecret(name string, value string) *Secret {
+	return &Secret{name: name, value: value}
+}
+
+// Name returns the name of this secret
+func (s *Secret) Name() string { return s.name }
+
+// Value returns the value of this secret
+func (s *Secret) Value() string { return s.value }
+
+// SecretSet defines the unordered set of all secrets for a session
+type SecretSet struct {
+	secrets       []*Secret
+	secretsByName map[string]*Secret
+}
+
+// NewSecretSet creates a new secret set from the given slice of secrets
+func NewSecretSet(secrets []*Secret) *SecretSet {
+	s := &SecretSet{secrets: secrets, secretsByName: make(map[string]*Secret, len(secrets))}
+	for _, secret := range s.secrets {
+		s.secretsByName[secret.name] = secret
+	}
+	return s
+}
+
+// FindByName finds the secret with the given name
+func (s *SecretSet) FindByName(name string) *Secret {
+	return s.secretsByName[name]
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type secretEnvelope struct {
+	Name  string `json:"name" validate:"required"`
+	Value string `json:"value" validate:"required"`
+}
+
+// ReadSecret reads a secret from the given JSON
+func ReadSecret(data json.RawMessage) (*Secret, error) {
+	var se secretEnvelope
+	if err := utils.UnmarshalAndValidate(data, &se, "secret"); err != nil {
+		return nil, err
+	}
+
+	return NewSecret(se.Name, se.Value), nil
+}
+
+// ReadSecretSet reads a secret set from the given JSON
+func ReadSecretSet(data json.RawMessage) (*SecretSet, error) {
+	items, err := utils.UnmarshalArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*Secret, len(items))
+	for s := range items {
+		if secrets[s], err = ReadSecret(items[s]); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewSecretSet(secrets), nil
+}
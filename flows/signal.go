@@ -0,0 +1,60 @@
+package flows
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// Signal describes an out-of-band signal, e.g. a payment confirmation, which a run received whilst it was waiting at
+// a signal wait. It renders as its name in a template, and has the following properties which can be accessed:
+//
+//  * `name` the name of the signal
+//  * `payload` the parsed JSON payload of the signal
+//  * `payload.[key]` sub-elements of the parsed JSON payload
+//  * `received_on` the time when the signal was received
+type Signal struct {
+	Name       string          `json:"name"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	ReceivedOn time.Time       `json:"received_on"`
+}
+
+// NewSignal creates a new signal
+func NewSignal(name string, payload json.RawMessage, receivedOn time.Time) *Signal {
+	return &Signal{Name: name, Payload: payload, ReceivedOn: receivedOn}
+}
+
+// Resolve resolves the given key when this signal is referenced in an expression
+func (s *Signal) Resolve(env utils.Environment, key string) types.XValue {
+	switch key {
+	case "name":
+		return types.NewXText(s.Name)
+	case "payload":
+		if s.Payload == nil {
+			return nil
+		}
+		return types.JSONToXValue(s.Payload)
+	case "received_on":
+		return types.NewXDateTime(s.ReceivedOn)
+	}
+
+	return types.NewXResolveError(s, key)
+}
+
+// Describe returns a representation of this type for error messages
+func (s *Signal) Describe() string { return "signal" }
+
+// Reduce is called when this object needs to be reduced to a primitive
+func (s *Signal) Reduce(env utils.Environment) types.XPrimitive {
+	return types.NewXText(s.Name)
+}
+
+// ToXJSON is called when this type is passed to @(json(...))
+func (s *Signal) ToXJSON(env utils.Environment) types.XText {
+	return types.ResolveKeys(env, s, "name", "payload", "received_on").ToXJSON(env)
+}
+
+var _ types.XValue = (*Signal)(nil)
+var _ types.XResolvable = (*Signal)(nil)
@@ -0,0 +1,82 @@
+package flows
+
+import (
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// TicketStatus is the status of a ticket
+type TicketStatus string
+
+// different statuses of tickets
+const (
+	TicketStatusOpen   TicketStatus = "open"
+	TicketStatusClosed TicketStatus = "closed"
+)
+
+// Ticket is a request for a human agent to take over a conversation, which was opened in a ticketer by a run. It
+// renders as its subject in a template, and has the following properties which can be accessed:
+//
+//  * `uuid` the UUID of the ticket
+//  * `subject` the subject of the ticket
+//  * `body` the body of the ticket
+//  * `status` the status of the ticket, one of `open` or `closed`
+//  * `assignee` the name of the agent who closed the ticket
+//  * `resolution_note` the note left by the agent when they closed the ticket
+type Ticket struct {
+	UUID           TicketUUID         `json:"uuid"`
+	Ticketer       *TicketerReference `json:"ticketer"`
+	Subject        string             `json:"subject"`
+	Body           string             `json:"body"`
+	Status         TicketStatus       `json:"status"`
+	Assignee       string             `json:"assignee,omitempty"`
+	ResolutionNote string             `json:"resolution_note,omitempty"`
+}
+
+// NewTicket creates a new open ticket
+func NewTicket(uuid TicketUUID, ticketer *TicketerReference, subject string, body string) *Ticket {
+	return &Ticket{UUID: uuid, Ticketer: ticketer, Subject: subject, Body: body, Status: TicketStatusOpen}
+}
+
+// Close closes this ticket with the given assignee and resolution note
+func (t *Ticket) Close(assignee string, resolutionNote string) {
+	t.Status = TicketStatusClosed
+	t.Assignee = assignee
+	t.ResolutionNote = resolutionNote
+}
+
+// Resolve resolves the given key when this ticket is referenced in an expression
+func (t *Ticket) Resolve(env utils.Environment, key string) types.XValue {
+	switch key {
+	case "uuid":
+		return types.NewXText(string(t.UUID))
+	case "subject":
+		return types.NewXText(t.Subject)
+	case "body":
+		return types.NewXText(t.Body)
+	case "status":
+		return types.NewXText(string(t.Status))
+	case "assignee":
+		return types.NewXText(t.Assignee)
+	case "resolution_note":
+		return types.NewXText(t.ResolutionNote)
+	}
+
+	return types.NewXResolveError(t, key)
+}
+
+// Describe returns a representation of this type for error messages
+func (t *Ticket) Describe() string { return "ticket" }
+
+// Reduce is called when this object needs to be reduced to a primitive
+func (t *Ticket) Reduce(env utils.Environment) types.XPrimitive {
+	return types.NewXText(t.Subject)
+}
+
+// ToXJSON is called when this type is passed to @(json(...))
+func (t *Ticket) ToXJSON(env utils.Environment) types.XText {
+	return types.ResolveKeys(env, t, "uuid", "subject", "body", "status", "assignee", "resolution_note").ToXJSON(env)
+}
+
+var _ types.XValue = (*Ticket)(nil)
+var _ types.XResolvable = (*Ticket)(nil)
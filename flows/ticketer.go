@@ -0,0 +1,84 @@
+package flows
+
+import (
+	"encoding/json"
+
+	"github.com/nyaruka/goflow/utils"
+)
+
+// Ticketer is a system which tickets can be opened in so that human agents can take over a conversation, e.g. a
+// helpdesk service
+type Ticketer struct {
+	uuid TicketerUUID
+	name string
+}
+
+// NewTicketer creates a new ticketer given the passed in uuid and name
+func NewTicketer(uuid TicketerUUID, name string) *Ticketer {
+	return &Ticketer{uuid, name}
+}
+
+// UUID returns the UUID of this ticketer
+func (t *Ticketer) UUID() TicketerUUID { return t.uuid }
+
+// Name returns the name of this ticketer
+func (t *Ticketer) Name() string { return t.name }
+
+// Reference returns a reference to this ticketer
+func (t *Ticketer) Reference() *TicketerReference { return NewTicketerReference(t.uuid, t.name) }
+
+// TicketerSet defines the unordered set of all ticketers for a session
+type TicketerSet struct {
+	ticketers       []*Ticketer
+	ticketersByUUID map[TicketerUUID]*Ticketer
+}
+
+// NewTicketerSet creates a new ticketer set from the given slice of ticketers
+func NewTicketerSet(ticketers []*Ticketer) *TicketerSet {
+	s := &TicketerSet{ticketers: ticketers, ticketersByUUID: make(map[TicketerUUID]*Ticketer, len(ticketers))}
+	for _, ticketer := range s.ticketers {
+		s.ticketersByUUID[ticketer.uuid] = ticketer
+	}
+	return s
+}
+
+// FindByUUID finds the ticketer with the given UUID
+func (s *TicketerSet) FindByUUID(uuid TicketerUUID) *Ticketer {
+	return s.ticketersByUUID[uuid]
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type ticketerEnvelope struct {
+	UUID TicketerUUID `json:"uuid" validate:"required,uuid"`
+	Name string       `json:"name"`
+}
+
+// ReadTicketer reads a ticketer from the given JSON
+func ReadTicketer(data json.RawMessage) (*Ticketer, error) {
+	var te ticketerEnvelope
+	if err := utils.UnmarshalAndValidate(data, &te, "ticketer"); err != nil {
+		return nil, err
+	}
+
+	return NewTicketer(te.UUID, te.Name), nil
+}
+
+// ReadTicketerSet reads a ticketer set from the given JSON
+func ReadTicketerSet(data json.RawMessage) (*TicketerSet, error) {
+	items, err := utils.UnmarshalArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketers := make([]*Ticketer, len(items))
+	for t := range items {
+		if ticketers[t], err = ReadTicketer(items[t]); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewTicketerSet(ticketers), nil
+}
@@ -18,17 +18,30 @@ type baseTriggerEnvelope struct {
 	TriggeredOn time.Time            `json:"triggered_on" validate:"required"`
 }
 
-func ReadTrigger(session flows.Session, envelope *utils.TypedEnvelope) (flows.Trigger, error) {
-	switch envelope.Type {
+// ReadFunc is a function which reads a trigger of a particular type from the passed in TypedEnvelope
+type ReadFunc func(session flows.Session, envelope *utils.TypedEnvelope) (flows.Trigger, error)
+
+var registeredTypes = map[string]ReadFunc{}
 
-	case TypeManual:
-		return ReadManualTrigger(session, envelope)
-	case TypeFlowAction:
-		return ReadFlowActionTrigger(session, envelope)
+// RegisterType registers a new type of trigger so that it can be read from JSON. Triggers may reference assets like
+// flows and contacts so are read with a function which has access to the session.
+func RegisterType(name string, readFunc ReadFunc) {
+	registeredTypes[name] = readFunc
+}
 
-	default:
+func init() {
+	RegisterType(TypeManual, ReadManualTrigger)
+	RegisterType(TypeFlowAction, ReadFlowActionTrigger)
+}
+
+// ReadTrigger attempts to read a trigger of a registered type from the passed in TypedEnvelope
+func ReadTrigger(session flows.Session, envelope *utils.TypedEnvelope) (flows.Trigger, error) {
+	readFunc := registeredTypes[envelope.Type]
+	if readFunc == nil {
 		return nil, fmt.Errorf("unknown trigger type: %s", envelope.Type)
 	}
+
+	return readFunc(session, envelope)
 }
 
 func unmarshalBaseTrigger(session flows.Session, base *baseTrigger, envelope *baseTriggerEnvelope) error {
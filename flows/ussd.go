@@ -0,0 +1,110 @@
+package flows
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// the navigation commands which contacts can send to USSD channels
+const (
+	USSDBack = "0"
+	USSDNext = "00"
+)
+
+// the navigation options added to paged USSD messages
+var ussdBackOption = fmt.Sprintf("%s. Back", USSDBack)
+var ussdNextOption = fmt.Sprintf("%s. Next", USSDNext)
+
+// PageUSSDMsg renders the given text and quick replies as a numbered menu for a USSD channel, which can only show a
+// single screen at a time. If that doesn't fit within the given maximum length then it is split into pages, each of
+// which ends with options to go back to the previous page or on to the next page.
+func PageUSSDMsg(text string, quickReplies []string, maxLength int) []string {
+	lines := make([]string, 0, 1+len(quickReplies))
+	if text != "" {
+		lines = append(lines, text)
+	}
+	for q, quickReply := range quickReplies {
+		lines = append(lines, fmt.Sprintf("%d. %s", q+1, quickReply))
+	}
+
+	// if everything fits on a single screen, there's no need for paging
+	single := strings.Join(lines, "\n")
+	if utf8.RuneCountInString(single) <= maxLength {
+		return []string{single}
+	}
+
+	// otherwise each page needs room for the navigation options
+	pageLength := maxLength - utf8.RuneCountInString(ussdBackOption) - utf8.RuneCountInString(ussdNextOption) - 2
+	if pageLength < 1 {
+		pageLength = 1
+	}
+
+	// break up any lines which are too long to fit on a page by themselves
+	chunks := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > pageLength {
+			chunks = append(chunks, wrapUSSDLine(line, pageLength)...)
+		} else {
+			chunks = append(chunks, line)
+		}
+	}
+
+	// and then fill pages with as many chunks as will fit
+	pages := make([]string, 0)
+	page := ""
+	for _, chunk := range chunks {
+		if page != "" && utf8.RuneCountInString(page)+1+utf8.RuneCountInString(chunk) > pageLength {
+			pages = append(pages, page)
+			page = ""
+		}
+		if page == "" {
+			page = chunk
+		} else {
+			page = page + "\n" + chunk
+		}
+	}
+	pages = append(pages, page)
+
+	for p := range pages {
+		if p > 0 {
+			pages[p] += "\n" + ussdBackOption
+		}
+		if p < len(pages)-1 {
+			pages[p] += "\n" + ussdNextOption
+		}
+	}
+	return pages
+}
+
+// wraps the given line into chunks no longer than the given length, breaking on spaces where possible
+func wrapUSSDLine(line string, length int) []string {
+	chunks := make([]string, 0, 1)
+	chunk := ""
+
+	for _, word := range strings.Fields(line) {
+		// words which can't fit on a line by themselves have to be broken up
+		for utf8.RuneCountInString(word) > length {
+			if chunk != "" {
+				chunks = append(chunks, chunk)
+				chunk = ""
+			}
+			runes := []rune(word)
+			chunks = append(chunks, string(runes[:length]))
+			word = string(runes[length:])
+		}
+
+		if chunk == "" {
+			chunk = word
+		} else if utf8.RuneCountInString(chunk)+1+utf8.RuneCountInString(word) <= length {
+			chunk = chunk + " " + word
+		} else {
+			chunks = append(chunks, chunk)
+			chunk = word
+		}
+	}
+	if chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,43 @@
+package flows_test
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nyaruka/goflow/flows"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageUSSDMsg(t *testing.T) {
+	tests := []struct {
+		text         string
+		quickReplies []string
+		maxLength    int
+		pages        []string
+	}{
+		{"Hi there", nil, 50, []string{"Hi there"}},
+		{"Pick one", []string{"Red", "Blue"}, 50, []string{"Pick one\n1. Red\n2. Blue"}},
+		{
+			"Pick a color",
+			[]string{"Red", "Blue", "Green", "Yellow"},
+			40,
+			[]string{"Pick a color\n1. Red\n00. Next", "2. Blue\n3. Green\n0. Back\n00. Next", "4. Yellow\n0. Back"},
+		},
+		{
+			"This is a long message which needs to be wrapped",
+			nil,
+			40,
+			[]string{"This is a long message\n00. Next", "which needs to be\n0. Back\n00. Next", "wrapped\n0. Back"},
+		},
+	}
+
+	for _, tc := range tests {
+		pages := flows.PageUSSDMsg(tc.text, tc.quickReplies, tc.maxLength)
+		assert.Equal(t, tc.pages, pages, "pages mismatch for text '%s'", tc.text)
+
+		for _, page := range pages {
+			assert.True(t, utf8.RuneCountInString(page) <= tc.maxLength, "page '%s' is longer than %d", page, tc.maxLength)
+		}
+	}
+}
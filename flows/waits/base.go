@@ -17,6 +17,9 @@ func (w *baseWait) Timeout() *int { return nil }
 // TimeoutOn would return when this wait times out for wait types that do that
 func (w *baseWait) TimeoutOn() *time.Time { return nil }
 
+// AllowedFlowTypes returns the flow types which this wait is allowed to occur in, which by default is all of them
+func (w *baseWait) AllowedFlowTypes() []flows.FlowType { return flows.AllFlowTypes }
+
 // Begin beings waiting
 func (w *baseWait) Begin(run flows.FlowRun) {}
 
@@ -24,8 +27,9 @@ func (w *baseWait) Begin(run flows.FlowRun) {}
 type baseTimeoutWait struct {
 	baseWait
 
-	Timeout_   *int       `json:"timeout,omitempty"`
-	TimeoutOn_ *time.Time `json:"timeout_on,omitempty"`
+	Timeout_      *int       `json:"timeout,omitempty"`
+	BusinessHours bool       `json:"business_hours,omitempty"`
+	TimeoutOn_    *time.Time `json:"timeout_on,omitempty"`
 }
 
 // Timeout returns the timeout of this wait in seconds or nil if no timeout is set
@@ -34,10 +38,19 @@ func (w *baseTimeoutWait) Timeout() *int { return w.Timeout_ }
 // TimeoutOn returns when this wait times out
 func (w *baseTimeoutWait) TimeoutOn() *time.Time { return w.TimeoutOn_ }
 
-// Begin beings waiting at this wait
+// Begin beings waiting at this wait. If the timeout is in business hours and the environment has a schedule, then
+// only working time in the timezone of the run counts towards the timeout.
 func (w *baseTimeoutWait) Begin(run flows.FlowRun) {
 	if w.Timeout_ != nil {
-		timeoutOn := time.Now().UTC().Add(time.Second * time.Duration(*w.Timeout_))
+		now := run.Environment().Now()
+		timeout := time.Second * time.Duration(*w.Timeout_)
+
+		var timeoutOn time.Time
+		if schedule := run.Environment().Schedule(); w.BusinessHours && schedule != nil {
+			timeoutOn = schedule.AddWorkingDuration(now, timeout).UTC()
+		} else {
+			timeoutOn = now.UTC().Add(timeout)
+		}
 
 		w.TimeoutOn_ = &timeoutOn
 	}
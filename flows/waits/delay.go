@@ -0,0 +1,69 @@
+package waits
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+const TypeDelay string = "delay"
+
+// DelayWait is a wait which waits until a point in time given by an expression, e.g. `@(datetime_add(now(), 3, "D"))`
+// or a contact field. It ignores any messages received whilst waiting and only resumes on a wait_timed_out event.
+type DelayWait struct {
+	baseWait
+
+	Until      string     `json:"until" validate:"required"`
+	TimeoutOn_ *time.Time `json:"timeout_on,omitempty"`
+}
+
+// NewDelayWait creates a new delay wait
+func NewDelayWait(until string) *DelayWait {
+	return &DelayWait{Until: until}
+}
+
+// Type returns the type of this wait
+func (w *DelayWait) Type() string { return TypeDelay }
+
+// TimeoutOn returns when this wait is due to resume
+func (w *DelayWait) TimeoutOn() *time.Time { return w.TimeoutOn_ }
+
+// Begin beings waiting at this wait. If our expression can't be evaluated to a time then there's no sensible time to
+// resume at, so we log a fatal error which fails the run.
+func (w *DelayWait) Begin(run flows.FlowRun, step flows.Step) error {
+	timeoutOn, err := w.evaluateUntil(run)
+	if err != nil {
+		return run.AddFatalError(step, nil, err)
+	}
+
+	timeoutOn = timeoutOn.UTC()
+	w.TimeoutOn_ = &timeoutOn
+
+	w.baseWait.Begin(run)
+
+	return run.ApplyEvent(step, nil, events.NewDelayWait(timeoutOn))
+}
+
+// evaluates our until expression to the time we should resume at
+func (w *DelayWait) evaluateUntil(run flows.FlowRun) (time.Time, error) {
+	value, err := run.EvaluateTemplate(w.Until)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	until, xerr := types.ToXDateTime(run.Environment(), value)
+	if xerr != nil {
+		return time.Time{}, fmt.Errorf("unable to evaluate delay until '%s': %s", w.Until, xerr)
+	}
+	return until.Native(), nil
+}
+
+// CanResume returns true only if a wait timed out event has been received
+func (w *DelayWait) CanResume(callerEvents []flows.Event) bool {
+	return containsEventOfType(callerEvents, events.TypeWaitTimedOut)
+}
+
+var _ flows.Wait = (*DelayWait)(nil)
@@ -7,17 +7,41 @@ import (
 	"github.com/nyaruka/goflow/utils"
 )
 
+var registeredTypes = map[string](func() flows.Wait){}
+
+// RegisterType registers a new type of wait so that it can be read from flow definitions and sessions. The given
+// function should return a new empty instance of the wait type which is then populated from JSON.
+func RegisterType(name string, initFunc func() flows.Wait) {
+	registeredTypes[name] = initFunc
+}
+
+func init() {
+	RegisterType(TypeNothing, func() flows.Wait { return &NothingWait{} })
+	RegisterType(TypeDelay, func() flows.Wait { return &DelayWait{} })
+	RegisterType(TypeMsg, func() flows.Wait { return &MsgWait{} })
+	RegisterType(TypeSignal, func() flows.Wait { return &SignalWait{} })
+	RegisterType(TypeTicket, func() flows.Wait { return &TicketWait{} })
+	RegisterType(TypeUSSD, func() flows.Wait { return &USSDWait{} })
+	RegisterType(TypeVoice, func() flows.Wait { return &VoiceWait{} })
+}
+
+// WaitFromEnvelope attempts to build a wait of a registered type from the passed in TypedEnvelope
 func WaitFromEnvelope(envelope *utils.TypedEnvelope) (flows.Wait, error) {
-	var wait flows.Wait
-
-	switch envelope.Type {
-	case TypeNothing:
-		wait = &NothingWait{}
-	case TypeMsg:
-		wait = &MsgWait{}
-	default:
+	initFunc := registeredTypes[envelope.Type]
+	if initFunc == nil {
 		return nil, fmt.Errorf("Unknown wait type: %s", envelope.Type)
 	}
 
+	wait := initFunc()
 	return wait, utils.UnmarshalAndValidate(envelope.Data, wait, fmt.Sprintf("wait[type=%s]", envelope.Type))
 }
+
+// CopyWait returns a copy of the given wait. Waits in flow definitions can be shared by many sessions, so each session
+// begins its own copy which holds the state of that session's wait, e.g. when it times out.
+func CopyWait(wait flows.Wait) (flows.Wait, error) {
+	envelope, err := utils.EnvelopeFromTyped(wait)
+	if err != nil {
+		return nil, err
+	}
+	return WaitFromEnvelope(envelope)
+}
@@ -0,0 +1,20 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeAudio is the type of our audio hint
+const TypeAudio string = "audio"
+
+// AudioHint requests a message with an audio attachment
+type AudioHint struct {
+	baseHint
+}
+
+// NewAudioHint creates a new audio hint
+func NewAudioHint() *AudioHint {
+	return &AudioHint{baseHint: newBaseHint(TypeAudio)}
+}
+
+var _ flows.Hint = (*AudioHint)(nil)
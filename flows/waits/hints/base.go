@@ -0,0 +1,49 @@
+package hints
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+)
+
+var registeredTypes = map[string](func() flows.Hint){}
+
+// RegisterType registers a new type of hint so that it can be read from flow definitions and sessions. The given
+// function should return a new empty instance of the hint type which is then populated from JSON.
+func RegisterType(name string, initFunc func() flows.Hint) {
+	registeredTypes[name] = initFunc
+}
+
+func init() {
+	RegisterType(TypeText, func() flows.Hint { return &TextHint{} })
+	RegisterType(TypeDigits, func() flows.Hint { return &DigitsHint{} })
+	RegisterType(TypeImage, func() flows.Hint { return &ImageHint{} })
+	RegisterType(TypeAudio, func() flows.Hint { return &AudioHint{} })
+	RegisterType(TypeVideo, func() flows.Hint { return &VideoHint{} })
+	RegisterType(TypeLocation, func() flows.Hint { return &LocationHint{} })
+	RegisterType(TypeDate, func() flows.Hint { return &DateHint{} })
+}
+
+// HintFromEnvelope attempts to build a hint of a registered type from the passed in TypedEnvelope
+func HintFromEnvelope(envelope *utils.TypedEnvelope) (flows.Hint, error) {
+	initFunc := registeredTypes[envelope.Type]
+	if initFunc == nil {
+		return nil, fmt.Errorf("Unknown hint type: %s", envelope.Type)
+	}
+
+	hint := initFunc()
+	return hint, utils.UnmarshalAndValidate(envelope.Data, hint, fmt.Sprintf("hint[type=%s]", envelope.Type))
+}
+
+// the base of all hint types, which includes the type so that hints can be marshaled directly
+type baseHint struct {
+	Type_ string `json:"type" validate:"required"`
+}
+
+func newBaseHint(typeName string) baseHint {
+	return baseHint{Type_: typeName}
+}
+
+// Type returns the type of this hint
+func (h *baseHint) Type() string { return h.Type_ }
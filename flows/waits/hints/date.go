@@ -0,0 +1,20 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeDate is the type of our date hint
+const TypeDate string = "date"
+
+// DateHint requests a message with a date, e.g. so that channels can show a date picker
+type DateHint struct {
+	baseHint
+}
+
+// NewDateHint creates a new date hint
+func NewDateHint() *DateHint {
+	return &DateHint{baseHint: newBaseHint(TypeDate)}
+}
+
+var _ flows.Hint = (*DateHint)(nil)
@@ -0,0 +1,28 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeDigits is the type of our digits hint
+const TypeDigits string = "digits"
+
+// DigitsHint requests a message containing only digits, optionally of a fixed count or terminated by a given
+// character, e.g. #
+type DigitsHint struct {
+	baseHint
+
+	Count        *int   `json:"count,omitempty" validate:"omitempty,min=1"`
+	TerminatedBy string `json:"terminated_by,omitempty" validate:"omitempty,len=1"`
+}
+
+// NewDigitsHint creates a new digits hint
+func NewDigitsHint(count *int, terminatedBy string) *DigitsHint {
+	return &DigitsHint{
+		baseHint:     newBaseHint(TypeDigits),
+		Count:        count,
+		TerminatedBy: terminatedBy,
+	}
+}
+
+var _ flows.Hint = (*DigitsHint)(nil)
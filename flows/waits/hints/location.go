@@ -0,0 +1,20 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeLocation is the type of our location hint
+const TypeLocation string = "location"
+
+// LocationHint requests a message with a location (i.e. a geo attachment)
+type LocationHint struct {
+	baseHint
+}
+
+// NewLocationHint creates a new location hint
+func NewLocationHint() *LocationHint {
+	return &LocationHint{baseHint: newBaseHint(TypeLocation)}
+}
+
+var _ flows.Hint = (*LocationHint)(nil)
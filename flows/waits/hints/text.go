@@ -0,0 +1,20 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeText is the type of our text hint
+const TypeText string = "text"
+
+// TextHint requests a message with plain text
+type TextHint struct {
+	baseHint
+}
+
+// NewTextHint creates a new text hint
+func NewTextHint() *TextHint {
+	return &TextHint{baseHint: newBaseHint(TypeText)}
+}
+
+var _ flows.Hint = (*TextHint)(nil)
@@ -0,0 +1,20 @@
+package hints
+
+import (
+	"github.com/nyaruka/goflow/flows"
+)
+
+// TypeVideo is the type of our video hint
+const TypeVideo string = "video"
+
+// VideoHint requests a message with a video attachment
+type VideoHint struct {
+	baseHint
+}
+
+// NewVideoHint creates a new video hint
+func NewVideoHint() *VideoHint {
+	return &VideoHint{baseHint: newBaseHint(TypeVideo)}
+}
+
+var _ flows.Hint = (*VideoHint)(nil)
@@ -1,30 +1,42 @@
 package waits
 
 import (
+	"encoding/json"
+
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/waits/hints"
+	"github.com/nyaruka/goflow/utils"
 )
 
 const TypeMsg string = "msg"
 
-// MsgWait is a wait which waits for an incoming message (i.e. a msg_received event)
+// MsgWait is a wait which waits for an incoming message (i.e. a msg_received event). It can optionally have a hint
+// which tells the caller what kind of message is expected.
 type MsgWait struct {
 	baseTimeoutWait
+
+	Hint flows.Hint `json:"hint,omitempty"`
 }
 
 // NewMsgWait creates a new message wait
-func NewMsgWait(timeout *int) *MsgWait {
-	return &MsgWait{baseTimeoutWait{Timeout_: timeout}}
+func NewMsgWait(timeout *int, hint flows.Hint) *MsgWait {
+	return &MsgWait{baseTimeoutWait: baseTimeoutWait{Timeout_: timeout}, Hint: hint}
 }
 
 // Type returns the type of this wait
 func (w *MsgWait) Type() string { return TypeMsg }
 
+// AllowedFlowTypes returns the flow types which this wait is allowed to occur in
+func (w *MsgWait) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeMessaging}
+}
+
 // Begin beings waiting at this wait
-func (w *MsgWait) Begin(run flows.FlowRun, step flows.Step) {
+func (w *MsgWait) Begin(run flows.FlowRun, step flows.Step) error {
 	w.baseTimeoutWait.Begin(run)
 
-	run.ApplyEvent(step, nil, events.NewMsgWait(w.TimeoutOn_))
+	return run.ApplyEvent(step, nil, events.NewMsgWait(w.TimeoutOn_, w.Hint))
 }
 
 // CanResume returns true if a message event has been received
@@ -36,3 +48,31 @@ func (w *MsgWait) CanResume(callerEvents []flows.Event) bool {
 }
 
 var _ flows.Wait = (*MsgWait)(nil)
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type msgWaitEnvelope struct {
+	baseTimeoutWait
+
+	Hint *utils.TypedEnvelope `json:"hint,omitempty"`
+}
+
+// UnmarshalJSON unmarshals a message wait from the given JSON
+func (w *MsgWait) UnmarshalJSON(data []byte) error {
+	envelope := &msgWaitEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return err
+	}
+
+	w.baseTimeoutWait = envelope.baseTimeoutWait
+
+	var err error
+	if envelope.Hint != nil {
+		if w.Hint, err = hints.HintFromEnvelope(envelope.Hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,10 +17,10 @@ type NothingWait struct {
 func (w *NothingWait) Type() string { return TypeNothing }
 
 // Begin beings waiting at this wait
-func (w *NothingWait) Begin(run flows.FlowRun, step flows.Step) {
+func (w *NothingWait) Begin(run flows.FlowRun, step flows.Step) error {
 	w.baseWait.Begin(run)
 
-	run.ApplyEvent(step, nil, events.NewNothingWait())
+	return run.ApplyEvent(step, nil, events.NewNothingWait())
 }
 
 // CanResume always returns true for a nothing wait because it's not waiting for anything
@@ -0,0 +1,48 @@
+package waits
+
+import (
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+const TypeSignal string = "signal"
+
+// SignalWait is a wait which waits for a named out-of-band signal (i.e. a signal_received event with that name)
+type SignalWait struct {
+	baseTimeoutWait
+
+	Signal string `json:"signal" validate:"required"`
+}
+
+// NewSignalWait creates a new signal wait
+func NewSignalWait(signal string, timeout *int) *SignalWait {
+	return &SignalWait{baseTimeoutWait: baseTimeoutWait{Timeout_: timeout}, Signal: signal}
+}
+
+// Type returns the type of this wait
+func (w *SignalWait) Type() string { return TypeSignal }
+
+// Begin beings waiting at this wait
+func (w *SignalWait) Begin(run flows.FlowRun, step flows.Step) error {
+	w.baseTimeoutWait.Begin(run)
+
+	// clear any signal received by an earlier wait so that it can't be mistaken for the one we're waiting for
+	run.SetSignal(nil)
+
+	return run.ApplyEvent(step, nil, events.NewSignalWait(w.Signal, w.TimeoutOn_))
+}
+
+// WaitsFor returns whether this wait is waiting for the signal with the given name
+func (w *SignalWait) WaitsFor(signal string) bool { return w.Signal == signal }
+
+// CanResume returns true if the signal we're waiting for has been received
+func (w *SignalWait) CanResume(callerEvents []flows.Event) bool {
+	for _, event := range callerEvents {
+		if event.Type() == events.TypeSignalReceived && w.WaitsFor(event.(*events.SignalReceivedEvent).Signal) {
+			return true
+		}
+	}
+	return w.baseTimeoutWait.CanResume(callerEvents)
+}
+
+var _ flows.Wait = (*SignalWait)(nil)
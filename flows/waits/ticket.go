@@ -0,0 +1,43 @@
+package waits
+
+import (
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+const TypeTicket string = "ticket"
+
+// TicketWait is a wait which waits for the last ticket opened by the run to be closed (i.e. a ticket_closed event)
+type TicketWait struct {
+	baseTimeoutWait
+}
+
+// NewTicketWait creates a new ticket wait
+func NewTicketWait(timeout *int) *TicketWait {
+	return &TicketWait{baseTimeoutWait{Timeout_: timeout}}
+}
+
+// Type returns the type of this wait
+func (w *TicketWait) Type() string { return TypeTicket }
+
+// Begin beings waiting at this wait
+func (w *TicketWait) Begin(run flows.FlowRun, step flows.Step) error {
+	w.baseTimeoutWait.Begin(run)
+
+	var ticketUUID flows.TicketUUID
+	if run.Ticket() != nil {
+		ticketUUID = run.Ticket().UUID
+	}
+
+	return run.ApplyEvent(step, nil, events.NewTicketWait(ticketUUID, w.TimeoutOn_))
+}
+
+// CanResume returns true if a ticket closed event has been received
+func (w *TicketWait) CanResume(callerEvents []flows.Event) bool {
+	if containsEventOfType(callerEvents, events.TypeTicketClosed) {
+		return true
+	}
+	return w.baseTimeoutWait.CanResume(callerEvents)
+}
+
+var _ flows.Wait = (*TicketWait)(nil)
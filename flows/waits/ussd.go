@@ -0,0 +1,45 @@
+package waits
+
+import (
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+)
+
+const TypeUSSD string = "ussd"
+
+// USSDWait is a wait which waits for the contact to reply on a USSD channel (i.e. a msg_received event). Whilst
+// waiting, the contact can send 00 to see the next page of the last message if it was split into pages, or 0 to see
+// the previous page, or to go back to the previous wait in the run if they're already on the first page.
+type USSDWait struct {
+	baseTimeoutWait
+}
+
+// NewUSSDWait creates a new USSD wait
+func NewUSSDWait(timeout *int) *USSDWait {
+	return &USSDWait{baseTimeoutWait{Timeout_: timeout}}
+}
+
+// Type returns the type of this wait
+func (w *USSDWait) Type() string { return TypeUSSD }
+
+// AllowedFlowTypes returns the flow types which this wait is allowed to occur in
+func (w *USSDWait) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeMessaging}
+}
+
+// Begin beings waiting at this wait
+func (w *USSDWait) Begin(run flows.FlowRun, step flows.Step) error {
+	w.baseTimeoutWait.Begin(run)
+
+	return run.ApplyEvent(step, nil, events.NewMsgWait(w.TimeoutOn_, nil))
+}
+
+// CanResume returns true if a message event has been received
+func (w *USSDWait) CanResume(callerEvents []flows.Event) bool {
+	if containsEventOfType(callerEvents, events.TypeMsgReceived) {
+		return true
+	}
+	return w.baseTimeoutWait.CanResume(callerEvents)
+}
+
+var _ flows.Wait = (*USSDWait)(nil)
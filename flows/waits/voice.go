@@ -0,0 +1,78 @@
+package waits
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/events"
+	"github.com/nyaruka/goflow/flows/waits/hints"
+	"github.com/nyaruka/goflow/utils"
+)
+
+const TypeVoice string = "voice"
+
+// VoiceWait is a wait in a voice flow which waits for the contact to respond on the current call (i.e. a msg_received
+// event). Its hint says what should be collected, which must be either DTMF digits or an audio recording.
+type VoiceWait struct {
+	baseTimeoutWait
+
+	Hint flows.Hint `json:"hint"`
+}
+
+// NewVoiceWait creates a new voice wait
+func NewVoiceWait(timeout *int, hint flows.Hint) *VoiceWait {
+	return &VoiceWait{baseTimeoutWait: baseTimeoutWait{Timeout_: timeout}, Hint: hint}
+}
+
+// Type returns the type of this wait
+func (w *VoiceWait) Type() string { return TypeVoice }
+
+// AllowedFlowTypes returns the flow types which this wait is allowed to occur in
+func (w *VoiceWait) AllowedFlowTypes() []flows.FlowType {
+	return []flows.FlowType{flows.FlowTypeVoice}
+}
+
+// Begin beings waiting at this wait
+func (w *VoiceWait) Begin(run flows.FlowRun, step flows.Step) error {
+	w.baseTimeoutWait.Begin(run)
+
+	return run.ApplyEvent(step, nil, events.NewVoiceWait(w.TimeoutOn_, w.Hint))
+}
+
+// CanResume returns true if a message event has been received
+func (w *VoiceWait) CanResume(callerEvents []flows.Event) bool {
+	if containsEventOfType(callerEvents, events.TypeMsgReceived) {
+		return true
+	}
+	return w.baseTimeoutWait.CanResume(callerEvents)
+}
+
+var _ flows.Wait = (*VoiceWait)(nil)
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type voiceWaitEnvelope struct {
+	baseTimeoutWait
+
+	Hint *utils.TypedEnvelope `json:"hint" validate:"required"`
+}
+
+// UnmarshalJSON unmarshals a voice wait from the given JSON
+func (w *VoiceWait) UnmarshalJSON(data []byte) error {
+	envelope := &voiceWaitEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, envelope, ""); err != nil {
+		return err
+	}
+
+	w.baseTimeoutWait = envelope.baseTimeoutWait
+
+	if envelope.Hint.Type != hints.TypeDigits && envelope.Hint.Type != hints.TypeAudio {
+		return fmt.Errorf("voice waits can only collect digits or audio, not %s", envelope.Hint.Type)
+	}
+
+	var err error
+	w.Hint, err = hints.HintFromEnvelope(envelope.Hint)
+	return err
+}
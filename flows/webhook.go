@@ -1,6 +1,8 @@
 package flows
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,22 +10,42 @@ import (
 	"mime"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/nyaruka/goflow/excellent/types"
 	"github.com/nyaruka/goflow/utils"
 )
 
-// response content-types that we'll save as @run.webhook.body
-var saveResponseContentTypes = map[string]bool{
-	"application/json":       true,
-	"application/javascript": true,
-	"application/xml":        true,
-	"text/html":              true,
-	"text/plain":             true,
-	"text/xml":               true,
+// response content-types, besides text/* and +json or +xml types, that we'll save as @run.webhook.body
+var textResponseContentTypes = map[string]bool{
+	"application/json":                  true,
+	"application/javascript":            true,
+	"application/xml":                   true,
+	"application/x-www-form-urlencoded": true,
 }
 
+// content-type of form-encoded request and response bodies
+const formContentType = "application/x-www-form-urlencoded"
+
+// returns whether the given media type is text which we can save, as opposed to binary
+func isTextContentType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") || textResponseContentTypes[mediaType]
+}
+
+// DefaultWebhookPayload is the template used for the body of webhooks posted to resthook subscribers, and by webhooks
+// migrated from legacy flows
+const DefaultWebhookPayload = `{
+	"contact": {"uuid": "@contact.uuid", "name": "@contact.name", "urn": @(json(if(default(run.input.urn, default(contact.urns.0, null)), text(default(run.input.urn, default(contact.urns.0, null))), null)))},
+	"flow": @(json(run.flow)),
+	"path": @(json(run.path)),
+	"results": @(json(run.results)),
+	"run": {"uuid": "@run.uuid", "created_on": "@run.created_on"},
+	"input": @(json(run.input)),
+	"channel": @(json(if(run.input, run.input.channel, null)))
+}`
+
 // WebhookStatus represents the status of a WebhookRequest
 type WebhookStatus string
 
@@ -46,11 +68,18 @@ func (r WebhookStatus) String() string {
 //
 //  * `status` the status of the webhook - one of "success", "connection_error" or "response_error"
 //  * `status_code` the status code of the response
-//  * `body` the body of the response
+//  * `content_type` the content type of the response, e.g. `application/json`
+//  * `body` the body of the response, which is empty if the response was binary
+//  * `body_size` the size of the response body in bytes
 //  * `json` the parsed JSON response (if response body was JSON)
 //  * `json.[key]` sub-elements of the parsed JSON response
+//  * `xml` the root element of the parsed XML response (if response body was XML)
+//  * `xml.[name]` child elements or attributes of the parsed XML response, with repeated elements as arrays
+//  * `form` the parsed values of a form-encoded response
+//  * `form.[key]` the value of a key in a form-encoded response
 //  * `request` the raw request made, including headers
 //  * `response` the raw response received, including headers
+//  * `attempts` the number of attempts made to call the webhook
 //
 // Examples:
 //
@@ -59,11 +88,14 @@ func (r WebhookStatus) String() string {
 //
 // @context webhook
 type WebhookCall struct {
-	url        string
-	status     WebhookStatus
-	statusCode int
-	request    string
-	response   string
+	url         string
+	status      WebhookStatus
+	statusCode  int
+	contentType string
+	bodySize    int
+	request     string
+	response    string
+	attempts    int
 }
 
 // MakeWebhookCall fires the passed in http request, returning any errors encountered. RequestResponse is always set
@@ -92,6 +124,63 @@ func MakeWebhookCall(session Session, request *http.Request) (*WebhookCall, erro
 	return newWebhookCallFromResponse(requestDump, response, session.EngineConfig().MaxWebhookResponseBytes())
 }
 
+// MakeWebhookCallWithRetries fires the passed in http request like MakeWebhookCall, but if the call fails with a
+// connection error or a 5xx response, it is retried up to the given number of times. The delay before each retry
+// starts at the webhook retry backoff of the engine config and doubles after each retry. If timeout is non-zero,
+// each attempt is abandoned after that duration. Each attempt is passed to the given callback as it completes, and
+// the last attempt is returned.
+func MakeWebhookCallWithRetries(session Session, request *http.Request, timeout time.Duration, retries int, onAttempt func(*WebhookCall, error)) (*WebhookCall, error) {
+	// read our body so that we can send it with every attempt
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		request.Body.Close()
+	}
+
+	backoff := session.EngineConfig().WebhookRetryBackoff()
+
+	for attempt := 1; ; attempt++ {
+		webhook, err := makeWebhookAttempt(session, request, body, timeout)
+		if webhook != nil {
+			webhook.attempts = attempt
+		}
+		onAttempt(webhook, err)
+
+		if attempt > retries || webhook == nil || !webhook.isRetryable() {
+			return webhook, err
+		}
+
+		// wait before retrying, unless our context is cancelled whilst we wait
+		select {
+		case <-session.Clock().After(backoff):
+		case <-request.Context().Done():
+			return webhook, err
+		}
+		backoff *= 2
+	}
+}
+
+// makes a single attempt at the given request, with a copy of the given body and an optional timeout
+func makeWebhookAttempt(session Session, request *http.Request, body []byte, timeout time.Duration) (*WebhookCall, error) {
+	ctx := request.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	attempt := request.WithContext(ctx)
+	if body != nil {
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+	}
+
+	return MakeWebhookCall(session, attempt)
+}
+
 // URL returns the full URL
 func (w *WebhookCall) URL() string { return w.url }
 
@@ -104,14 +193,37 @@ func (w *WebhookCall) Status() WebhookStatus { return w.status }
 // StatusCode returns the response status code
 func (w *WebhookCall) StatusCode() int { return w.statusCode }
 
+// ContentType returns the media type of the response
+func (w *WebhookCall) ContentType() string { return w.contentType }
+
+// BodySize returns the size of the response body in bytes
+func (w *WebhookCall) BodySize() int { return w.bodySize }
+
 // Request returns the request trace
 func (w *WebhookCall) Request() string { return w.request }
 
 // Response returns the response trace
 func (w *WebhookCall) Response() string { return w.response }
 
-// Body returns the response body
+// Attempts returns the number of attempts made to call this webhook
+func (w *WebhookCall) Attempts() int { return w.attempts }
+
+// isRetryable returns whether this call failed in a way that might not happen if it were tried again
+func (w *WebhookCall) isRetryable() bool {
+	return w.status == WebhookStatusConnectionError || w.statusCode/100 == 5
+}
+
+// isBinary returns whether the response body was binary, in which case it wasn't saved
+func (w *WebhookCall) isBinary() bool {
+	return w.contentType != "" && !isTextContentType(w.contentType)
+}
+
+// Body returns the response body, which is empty if the response was binary
 func (w *WebhookCall) Body() string {
+	if w.isBinary() {
+		return ""
+	}
+
 	parts := strings.SplitN(w.response, "\r\n\r\n", 2)
 	if len(parts) == 2 {
 		return parts[1]
@@ -122,6 +234,35 @@ func (w *WebhookCall) Body() string {
 // JSON returns the response as a JSON fragment
 func (w *WebhookCall) JSON() types.XValue { return types.JSONToXValue([]byte(w.Body())) }
 
+// XML returns the root element of the response parsed as XML
+func (w *WebhookCall) XML() types.XValue { return types.XMLToXValue([]byte(w.Body())) }
+
+// Form returns the values of the response parsed as form-encoded, with repeated keys as arrays
+func (w *WebhookCall) Form() types.XValue {
+	if w.contentType != formContentType {
+		return types.NewXErrorf("webhook response isn't form-encoded")
+	}
+
+	values, err := url.ParseQuery(w.Body())
+	if err != nil {
+		return types.NewXError(err)
+	}
+
+	form := types.NewEmptyXMap()
+	for key, vals := range values {
+		if len(vals) == 1 {
+			form.Put(key, types.NewXText(vals[0]))
+		} else {
+			items := make([]types.XValue, len(vals))
+			for v := range vals {
+				items[v] = types.NewXText(vals[v])
+			}
+			form.Put(key, types.NewXArray(items...))
+		}
+	}
+	return form
+}
+
 // Resolve resolves the given key when this webhook is referenced in an expression
 func (w *WebhookCall) Resolve(env utils.Environment, key string) types.XValue {
 	switch key {
@@ -135,8 +276,20 @@ func (w *WebhookCall) Resolve(env utils.Environment, key string) types.XValue {
 		return types.NewXText(string(w.Status()))
 	case "status_code":
 		return types.NewXNumberFromInt(w.StatusCode())
+	case "content_type":
+		return types.NewXText(w.ContentType())
+	case "body":
+		return types.NewXText(w.Body())
+	case "body_size":
+		return types.NewXNumberFromInt(w.BodySize())
 	case "json":
 		return w.JSON()
+	case "xml":
+		return w.XML()
+	case "form":
+		return w.Form()
+	case "attempts":
+		return types.NewXNumberFromInt(w.Attempts())
 	}
 
 	return types.NewXResolveError(w, key)
@@ -152,7 +305,7 @@ func (w *WebhookCall) Reduce(env utils.Environment) types.XPrimitive {
 
 // ToXJSON is called when this type is passed to @(json(...))
 func (w *WebhookCall) ToXJSON(env utils.Environment) types.XText {
-	return types.ResolveKeys(env, w, "body", "json", "url", "request", "response", "status", "status_code").ToXJSON(env)
+	return types.ResolveKeys(env, w, "body", "json", "xml", "form", "url", "request", "response", "status", "status_code", "content_type", "body_size", "attempts").ToXJSON(env)
 }
 
 var _ types.XValue = (*WebhookCall)(nil)
@@ -166,6 +319,7 @@ func newWebhookCallFromError(request *http.Request, requestTrace string, request
 		statusCode: 0,
 		request:    requestTrace,
 		response:   requestError.Error(),
+		attempts:   1,
 	}
 }
 
@@ -177,6 +331,7 @@ func newWebhookCallFromResponse(requestTrace string, response *http.Response, ma
 		url:        response.Request.URL.String(),
 		statusCode: response.StatusCode,
 		request:    requestTrace,
+		attempts:   1,
 	}
 
 	// set our status based on our status code
@@ -193,29 +348,50 @@ func newWebhookCallFromResponse(requestTrace string, response *http.Response, ma
 	}
 	w.response = string(responseDump)
 
-	// only save response body's if we have a supported content-type
-	contentType := response.Header.Get("Content-Type")
-	mediaType, _, _ := mime.ParseMediaType(contentType)
-	saveBody := saveResponseContentTypes[mediaType]
+	// only read up to our max body bytes limit
+	bodyReader := io.LimitReader(response.Body, int64(maxBodyBytes)+1)
 
-	if saveBody {
-		// only read up to our max body bytes limit
-		bodyReader := io.LimitReader(response.Body, int64(maxBodyBytes)+1)
+	bodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, err
+	}
 
-		bodyBytes, err := ioutil.ReadAll(bodyReader)
-		if err != nil {
-			return nil, err
-		}
+	// if the response doesn't say what its content-type is, try to detect it
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" && len(bodyBytes) > 0 {
+		contentType = http.DetectContentType(bodyBytes)
+	}
+	w.contentType, _, _ = mime.ParseMediaType(contentType)
 
+	if len(bodyBytes) == 0 || isTextContentType(w.contentType) {
 		// if we have no remaining bytes, error because the body was too big
 		if bodyReader.(*io.LimitedReader).N <= 0 {
 			return nil, fmt.Errorf("webhook response body exceeds %d bytes limit", maxBodyBytes)
 		}
 
+		w.bodySize = len(bodyBytes)
 		w.response += string(bodyBytes)
 	} else {
-		// no body for non-text responses but add it to our Response log so users know why
-		w.response += "Non-text body, ignoring"
+		// binary bodies aren't saved, but we summarize them in the response trace, using the content length if the
+		// response has one, and otherwise reading at most another max body bytes
+		if response.ContentLength >= 0 {
+			w.bodySize = int(response.ContentLength)
+			w.response += fmt.Sprintf("Binary body of %d bytes with content type %s, ignoring", w.bodySize, w.contentType)
+		} else {
+			remainingReader := io.LimitReader(response.Body, int64(maxBodyBytes))
+			remaining, err := io.Copy(ioutil.Discard, remainingReader)
+			if err != nil {
+				return nil, err
+			}
+
+			w.bodySize = len(bodyBytes) + int(remaining)
+
+			if remainingReader.(*io.LimitedReader).N <= 0 {
+				w.response += fmt.Sprintf("Binary body of at least %d bytes with content type %s, ignoring", w.bodySize, w.contentType)
+			} else {
+				w.response += fmt.Sprintf("Binary body of %d bytes with content type %s, ignoring", w.bodySize, w.contentType)
+			}
+		}
 	}
 
 	return w, nil
@@ -246,11 +422,14 @@ func findMockedRequest(session Session, request *http.Request) *WebhookMock {
 //------------------------------------------------------------------------------------------
 
 type webhookCallEnvelope struct {
-	URL        string        `json:"url"`
-	Status     WebhookStatus `json:"status"`
-	StatusCode int           `json:"status_code"`
-	Request    string        `json:"request"`
-	Response   string        `json:"response"`
+	URL         string        `json:"url"`
+	Status      WebhookStatus `json:"status"`
+	StatusCode  int           `json:"status_code"`
+	ContentType string        `json:"content_type,omitempty"`
+	BodySize    int           `json:"body_size,omitempty"`
+	Request     string        `json:"request"`
+	Response    string        `json:"response"`
+	Attempts    int           `json:"attempts,omitempty"`
 }
 
 // UnmarshalJSON unmarshals a request response from the given JSON
@@ -266,18 +445,29 @@ func (w *WebhookCall) UnmarshalJSON(data []byte) error {
 	w.url = envelope.URL
 	w.status = envelope.Status
 	w.statusCode = envelope.StatusCode
+	w.contentType = envelope.ContentType
+	w.bodySize = envelope.BodySize
 	w.request = envelope.Request
 	w.response = envelope.Response
+	w.attempts = envelope.Attempts
+
+	// webhook calls saved before attempts were counted were only ever attempted once
+	if w.attempts == 0 {
+		w.attempts = 1
+	}
 	return nil
 }
 
 // MarshalJSON marshals this request response into JSON
 func (r *WebhookCall) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&webhookCallEnvelope{
-		URL:        r.url,
-		Status:     r.status,
-		StatusCode: r.statusCode,
-		Request:    r.request,
-		Response:   r.response,
+		URL:         r.url,
+		Status:      r.status,
+		StatusCode:  r.statusCode,
+		ContentType: r.contentType,
+		BodySize:    r.bodySize,
+		Request:     r.request,
+		Response:    r.response,
+		Attempts:    r.attempts,
 	})
 }
@@ -3,10 +3,17 @@ package flows_test
 import (
 	"github.com/nyaruka/goflow/flows"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nyaruka/goflow/excellent"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows/assets"
+	"github.com/nyaruka/goflow/flows/engine"
 	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,9 +30,11 @@ type call struct {
 func (c *call) String() string { return c.method + " " + c.url }
 
 type webhook struct {
-	request  string
-	response string
-	body     string
+	request     string
+	response    string
+	contentType string
+	body        string
+	bodySize    int
 }
 
 func TestWebhookParsing(t *testing.T) {
@@ -45,49 +54,91 @@ func TestWebhookParsing(t *testing.T) {
 			// successful GET
 			call: call{"GET", "http://127.0.0.1:49994/?cmd=success", ""},
 			webhook: webhook{
-				request:  "GET /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
-				response: "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
-				body:     "{ \"ok\": \"true\" }",
+				request:     "GET /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
+				contentType: "text/plain",
+				body:        "{ \"ok\": \"true\" }",
+				bodySize:    16,
 			},
 		}, {
 			// successful POST without body
 			call: call{"POST", "http://127.0.0.1:49994/?cmd=success", ""},
 			webhook: webhook{
-				request:  "POST /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 0\r\nAccept-Encoding: gzip\r\n\r\n",
-				response: "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
-				body:     "{ \"ok\": \"true\" }",
+				request:     "POST /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 0\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
+				contentType: "text/plain",
+				body:        "{ \"ok\": \"true\" }",
+				bodySize:    16,
 			},
 		}, {
 			// successful POST with body
 			call: call{"POST", "http://127.0.0.1:49994/?cmd=success", `{"contact": "Bob"}`},
 			webhook: webhook{
-				request:  "POST /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 18\r\nAccept-Encoding: gzip\r\n\r\n{\"contact\": \"Bob\"}",
-				response: "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
-				body:     "{ \"ok\": \"true\" }",
+				request:     "POST /?cmd=success HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 18\r\nAccept-Encoding: gzip\r\n\r\n{\"contact\": \"Bob\"}",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 16\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"ok\": \"true\" }",
+				contentType: "text/plain",
+				body:        "{ \"ok\": \"true\" }",
+				bodySize:    16,
 			},
 		}, {
 			// POST returning 503
 			call: call{"POST", "http://127.0.0.1:49994/?cmd=unavailable", ""},
 			webhook: webhook{
-				request:  "POST /?cmd=unavailable HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 0\r\nAccept-Encoding: gzip\r\n\r\n",
-				response: "HTTP/1.1 503 Service Unavailable\r\nContent-Length: 37\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"errors\": [\"service unavailable\"] }",
-				body:     "{ \"errors\": [\"service unavailable\"] }",
+				request:     "POST /?cmd=unavailable HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nContent-Length: 0\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 503 Service Unavailable\r\nContent-Length: 37\r\nContent-Type: text/plain; charset=utf-8\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n{ \"errors\": [\"service unavailable\"] }",
+				contentType: "text/plain",
+				body:        "{ \"errors\": [\"service unavailable\"] }",
+				bodySize:    37,
 			},
 		}, {
 			// GET returning non-text content type
 			call: call{"GET", "http://127.0.0.1:49994/?cmd=binary", ""},
 			webhook: webhook{
-				request:  "GET /?cmd=binary HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
-				response: "HTTP/1.1 200 OK\r\nContent-Length: 10\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nNon-text body, ignoring",
-				body:     "Non-text body, ignoring",
+				request:     "GET /?cmd=binary HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 10\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nBinary body of 10 bytes with content type application/octet-stream, ignoring",
+				contentType: "application/octet-stream",
+				body:        "",
+				bodySize:    10,
 			},
 		}, {
 			// GET returning binary body larger than allowed (we ignore binary body so no biggie)
 			call: call{"GET", "http://127.0.0.1:49994/?cmd=binary&size=11000", ""},
 			webhook: webhook{
-				request:  "GET /?cmd=binary&size=11000 HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
-				response: "HTTP/1.1 200 OK\r\nContent-Length: 11000\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nNon-text body, ignoring",
-				body:     "Non-text body, ignoring",
+				request:     "GET /?cmd=binary&size=11000 HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 11000\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nBinary body of 11000 bytes with content type application/octet-stream, ignoring",
+				contentType: "application/octet-stream",
+				body:        "",
+				bodySize:    11000,
+			},
+		}, {
+			// GET returning binary body without a content length
+			call: call{"GET", "http://127.0.0.1:49994/?cmd=binary&size=15000&chunked=true", ""},
+			webhook: webhook{
+				request:     "GET /?cmd=binary&size=15000&chunked=true HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nBinary body of 15000 bytes with content type application/octet-stream, ignoring",
+				contentType: "application/octet-stream",
+				body:        "",
+				bodySize:    15000,
+			},
+		}, {
+			// GET returning binary body without a content length which is too big to read it all
+			call: call{"GET", "http://127.0.0.1:49994/?cmd=binary&size=50000&chunked=true", ""},
+			webhook: webhook{
+				request:     "GET /?cmd=binary&size=50000&chunked=true HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\nContent-Type: application/octet-stream\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\nBinary body of at least 20001 bytes with content type application/octet-stream, ignoring",
+				contentType: "application/octet-stream",
+				body:        "",
+				bodySize:    20001,
+			},
+		}, {
+			// GET returning XML
+			call: call{"GET", "http://127.0.0.1:49994/?cmd=echo&type=application%2Fxml&content=%3Cok%3Etrue%3C%2Fok%3E", ""},
+			webhook: webhook{
+				request:     "GET /?cmd=echo&type=application%2Fxml&content=%3Cok%3Etrue%3C%2Fok%3E HTTP/1.1\r\nHost: 127.0.0.1:49994\r\nUser-Agent: goflow-testing\r\nAccept-Encoding: gzip\r\n\r\n",
+				response:    "HTTP/1.1 200 OK\r\nContent-Length: 13\r\nContent-Type: application/xml\r\nDate: Wed, 11 Apr 2018 18:24:30 GMT\r\n\r\n<ok>true</ok>",
+				contentType: "application/xml",
+				body:        "<ok>true</ok>",
+				bodySize:    13,
 			},
 		}, {
 			// GET returning text body larger than allowed
@@ -108,7 +159,131 @@ func TestWebhookParsing(t *testing.T) {
 			assert.Equal(t, tc.call.method, webhook.Method(), "method mismatch for call %s", tc.call)
 			assert.Equal(t, tc.webhook.request, webhook.Request(), "request trace mismatch for call %s", tc.call)
 			assert.Equal(t, tc.webhook.response, webhook.Response(), "response mismatch for call %s", tc.call)
+			assert.Equal(t, tc.webhook.contentType, webhook.ContentType(), "content type mismatch for call %s", tc.call)
 			assert.Equal(t, tc.webhook.body, webhook.Body(), "body mismatch for call %s", tc.call)
+			assert.Equal(t, tc.webhook.bodySize, webhook.BodySize(), "body size mismatch for call %s", tc.call)
 		}
 	}
 }
+
+func TestWebhookContext(t *testing.T) {
+	server, err := test.NewTestHTTPServer(testServerPort)
+	require.NoError(t, err)
+	defer server.Close()
+
+	session, err := test.CreateTestSession(testServerPort, nil)
+	require.NoError(t, err)
+
+	echoURL := func(contentType, content string) string {
+		return "http://127.0.0.1:49994/?cmd=echo&type=" + url.QueryEscape(contentType) + "&content=" + url.QueryEscape(content)
+	}
+
+	testCases := []struct {
+		url      string
+		lookup   string
+		expected string
+		hasError bool
+	}{
+		{echoURL("application/json", `{"results": [{"state": "WA"}]}`), "json.results.0.state", "WA", false},
+		{echoURL("application/json", `{"results": [{"state": "WA"}]}`), "xml", "", true},
+		{echoURL("application/json", `{"results": [{"state": "WA"}]}`), "form", "", true},
+		{echoURL("application/json", `{"results": [{"state": "WA"}]}`), "content_type", "application/json", false},
+
+		{echoURL("text/xml", `<response status="ok"><state>WA</state></response>`), "xml.state", "WA", false},
+		{echoURL("text/xml", `<response status="ok"><state>WA</state></response>`), "xml.status", "ok", false},
+		{echoURL("application/xml", `<r><city>Seattle</city><city>Spokane</city></r>`), "xml.city.1", "Spokane", false},
+		{echoURL("application/xml", `<r><city>Seattle</city><city>Spokane</city></r>`), "xml.zip", "", true},
+		{echoURL("application/xml", `<r><city>Seattle</city><city>Spokane</city></r>`), "json", "", true},
+
+		{echoURL("application/x-www-form-urlencoded", `state=WA&city=Seattle&city=Spokane`), "form.state", "WA", false},
+		{echoURL("application/x-www-form-urlencoded", `state=WA&city=Seattle&city=Spokane`), "form.city.0", "Seattle", false},
+		{echoURL("application/x-www-form-urlencoded", `state=WA&city=Seattle&city=Spokane`), "form.zip", "", true},
+		{echoURL("application/x-www-form-urlencoded", `state=WA&city=Seattle&city=Spokane`), "body_size", "34", false},
+
+		{"http://127.0.0.1:49994/?cmd=binary&type=image%2Fpng&size=20", "content_type", "image/png", false},
+		{"http://127.0.0.1:49994/?cmd=binary&type=image%2Fpng&size=20", "body_size", "20", false},
+		{"http://127.0.0.1:49994/?cmd=binary&type=image%2Fpng&size=20", "body", "", false},
+	}
+
+	env := session.Environment()
+
+	for _, tc := range testCases {
+		request, err := http.NewRequest("GET", tc.url, nil)
+		require.NoError(t, err)
+
+		webhook, err := flows.MakeWebhookCall(session, request)
+		require.NoError(t, err)
+
+		value := excellent.ResolveValue(env, webhook, tc.lookup)
+
+		if tc.hasError {
+			assert.True(t, types.IsXError(value), "expected error resolving '%s' for %s", tc.lookup, tc.url)
+		} else {
+			assert.False(t, types.IsXError(value), "unexpected error resolving '%s' for %s: %s", tc.lookup, tc.url, value)
+
+			text, _ := types.ToXText(env, value)
+			assert.Equal(t, tc.expected, text.Native(), "value mismatch resolving '%s' for %s", tc.lookup, tc.url)
+		}
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	server, err := test.NewTestHTTPServer(testServerPort)
+	require.NoError(t, err)
+	defer server.Close()
+
+	// use a fixed clock so that waiting between retries doesn't slow down our tests
+	now := time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC)
+	clock := utils.NewFixedClock(now)
+	config := engine.NewConfigBuilder().WithWebhookRetryBackoff(time.Second).WithClock(clock).Build()
+	session := engine.NewSession(assets.NewAssetCache(100, 5), assets.NewMockAssetServer(), config, test.TestHTTPClient)
+
+	testCases := []struct {
+		call             call
+		timeout          time.Duration
+		retries          int
+		expectedStatuses []flows.WebhookStatus
+		expectedBackoff  time.Duration
+	}{
+		// successful calls aren't retried
+		{call{"GET", "http://127.0.0.1:49994/?cmd=success", ""}, 0, 2, []flows.WebhookStatus{"success"}, 0},
+
+		// 5xx responses are retried until we run out of retries, with the backoff doubling after each retry
+		{call{"POST", "http://127.0.0.1:49994/?cmd=unavailable", `{"contact": "Bob"}`}, 0, 2, []flows.WebhookStatus{"response_error", "response_error", "response_error"}, 3 * time.Second},
+
+		// other error responses aren't retried
+		{call{"GET", "http://127.0.0.1:49994/?cmd=gone", ""}, 0, 2, []flows.WebhookStatus{"response_error"}, 0},
+
+		// calls which time out are connection errors and are retried
+		{call{"GET", "http://127.0.0.1:49994/?cmd=slow&delay=200", ""}, 50 * time.Millisecond, 1, []flows.WebhookStatus{"connection_error", "connection_error"}, time.Second},
+		{call{"GET", "http://127.0.0.1:49994/?cmd=slow&delay=10", ""}, time.Second, 1, []flows.WebhookStatus{"success"}, 0},
+	}
+
+	for _, tc := range testCases {
+		clock.SetNow(now)
+
+		request, err := http.NewRequest(tc.call.method, tc.call.url, strings.NewReader(tc.call.body))
+		require.NoError(t, err)
+
+		attempts := make([]*flows.WebhookCall, 0)
+		webhook, _ := flows.MakeWebhookCallWithRetries(session, request, tc.timeout, tc.retries, func(attempt *flows.WebhookCall, err error) {
+			attempts = append(attempts, attempt)
+		})
+
+		require.Equal(t, len(tc.expectedStatuses), len(attempts), "attempts mismatch for call %s", tc.call)
+
+		for a, attempt := range attempts {
+			assert.Equal(t, tc.expectedStatuses[a], attempt.Status(), "status mismatch for attempt %d of call %s", a+1, tc.call)
+			assert.Equal(t, a+1, attempt.Attempts(), "attempt count mismatch for attempt %d of call %s", a+1, tc.call)
+
+			// every attempt sends the full body
+			assert.True(t, strings.HasSuffix(attempt.Request(), "\r\n\r\n"+tc.call.body), "body missing from attempt %d of call %s", a+1, tc.call)
+		}
+
+		assert.Equal(t, attempts[len(attempts)-1], webhook)
+		assert.Equal(t, len(attempts), webhook.Attempts())
+
+		// waits between retries are made on the session clock
+		assert.Equal(t, now.Add(tc.expectedBackoff), clock.Now(), "backoff mismatch for call %s", tc.call)
+	}
+}
@@ -17,16 +17,6 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-var legacyWebhookBody = `{
-	"contact": {"uuid": "@contact.uuid", "name": "@contact.name", "urn": @(json(if(default(run.input.urn, default(contact.urns.0, null)), text(default(run.input.urn, default(contact.urns.0, null))), null)))},
-	"flow": @(json(run.flow)),
-	"path": @(json(run.path)),
-	"results": @(json(run.results)),
-	"run": {"uuid": "@run.uuid", "created_on": "@run.created_on"},
-	"input": @(json(run.input)),
-	"channel": @(json(if(run.input, run.input.channel, null)))
-}`
-
 // Flow is a flow in the legacy format
 type Flow struct {
 	BaseLanguage utils.Language `json:"base_language"`
@@ -260,6 +250,10 @@ type webhookTest struct {
 	Status string `json:"status"`
 }
 
+type airtimeTest struct {
+	ExitStatus string `json:"exit_status"`
+}
+
 type localizedStringTest struct {
 	Test Translations `json:"test"`
 }
@@ -585,7 +579,7 @@ func migrateAction(baseLanguage utils.Language, a Action, localization flows.Loc
 
 		if strings.ToUpper(a.Action) == "POST" {
 			headers["Content-Type"] = "application/json"
-			body = legacyWebhookBody
+			body = flows.DefaultWebhookPayload
 		}
 
 		for _, header := range a.WebhookHeaders {
@@ -680,6 +674,16 @@ func migrateRule(baseLanguage utils.Language, exitMap map[string]flows.Exit, r R
 			arguments = []string{"response_error"}
 		}
 
+	case "airtime_status":
+		newType = "has_airtime_status"
+		test := airtimeTest{}
+		err = json.Unmarshal(r.Test.Data, &test)
+		if test.ExitStatus == "success" {
+			arguments = []string{"success"}
+		} else {
+			arguments = []string{"failed"}
+		}
+
 	case "timeout":
 		omitOperand = true
 		arguments = []string{"@run"}
@@ -711,7 +715,6 @@ func migrateRule(baseLanguage utils.Language, exitMap map[string]flows.Exit, r R
 	}
 
 	// TODO
-	// airtime_status
 	// ward / district / state
 	// interrupted_status
 
@@ -804,6 +807,11 @@ func parseRules(baseLanguage utils.Language, r RuleSet, localization flows.Local
 			// webhook failures don't have a case, instead they become the default
 			defaultExitUUID = exitMap[r.Rules[i].Category.Base(baseLanguage)].UUID()
 		}
+
+		if r.Rules[i].Test.Type == "airtime_status" && c.Arguments[0] == "failed" {
+			// airtime failures also become the default so runs without a transfer take that exit
+			defaultExitUUID = exitMap[r.Rules[i].Category.Base(baseLanguage)].UUID()
+		}
 	}
 
 	// for webhook rulesets we need to map 2 rules (success/failure) to 3 cases and exits (success/response_error/connection_error)
@@ -825,6 +833,12 @@ func parseRules(baseLanguage utils.Language, r RuleSet, localization flows.Local
 	return exits, cases, defaultExitUUID, nil
 }
 
+// legacy airtime rulesets are configured with an amount and currency for each country
+type airtimeConfig map[string]struct {
+	CurrencyCode string          `json:"currency_code"`
+	Amount       decimal.Decimal `json:"amount"`
+}
+
 type fieldConfig struct {
 	FieldDelimiter string `json:"field_delimiter"`
 	FieldIndex     int    `json:"field_index"`
@@ -877,7 +891,7 @@ func migrateRuleSet(lang utils.Language, r RuleSet, localization flows.Localizat
 
 		if strings.ToUpper(config.Action) == "POST" {
 			headers["Content-Type"] = "application/json"
-			body = legacyWebhookBody
+			body = flows.DefaultWebhookPayload
 		}
 
 		for _, header := range config.Headers {
@@ -897,6 +911,28 @@ func migrateRuleSet(lang utils.Language, r RuleSet, localization flows.Localizat
 		// webhook rulesets operate on the webhook call
 		router = routers.NewSwitchRouter(defaultExit, "@run.webhook", cases, resultName)
 
+	case "airtime":
+		var config airtimeConfig
+		err := json.Unmarshal(r.Config, &config)
+		if err != nil {
+			return nil, err
+		}
+
+		amounts := make(map[string]decimal.Decimal, len(config))
+		for _, countryConfig := range config {
+			amounts[countryConfig.CurrencyCode] = countryConfig.Amount
+		}
+
+		newActions = []flows.Action{
+			&actions.TransferAirtimeAction{
+				BaseAction: actions.NewBaseAction(flows.ActionUUID(utils.NewUUID())),
+				Amounts:    amounts,
+			},
+		}
+
+		// airtime rulesets operate on the airtime transfer
+		router = routers.NewSwitchRouter(defaultExit, "@run.airtime", cases, resultName)
+
 	case "form_field":
 		var config fieldConfig
 		json.Unmarshal(r.Config, &config)
@@ -924,7 +960,7 @@ func migrateRuleSet(lang utils.Language, r RuleSet, localization flows.Localizat
 			}
 		}
 
-		wait = waits.NewMsgWait(timeout)
+		wait = waits.NewMsgWait(timeout, nil)
 
 		fallthrough
 	case "flow_field":
@@ -1054,6 +1090,7 @@ func (f *Flow) Migrate(includeUI bool) (flows.Flow, error) {
 	return definition.NewFlow(
 		f.Metadata.UUID,
 		f.Metadata.Name,
+		flows.FlowTypeMessaging,
 		f.Metadata.Revision,
 		f.BaseLanguage,
 		f.Metadata.Expires,
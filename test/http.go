@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"time"
 
 	"github.com/nyaruka/goflow/utils"
 )
@@ -38,6 +39,9 @@ func testHTTPHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{ "ok": "true" }`))
 	case "echo":
+		if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+			w.Header().Set("Content-Type", typeParam)
+		}
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(r.URL.Query().Get("content")))
 	case "binary":
@@ -56,12 +60,24 @@ func testHTTPHandler(w http.ResponseWriter, r *http.Request) {
 			data[i] = byte(40 + i%10)
 		}
 
+		// chunked responses don't tell us their length up front
 		w.Header().Set("Content-Type", typeParam)
-		w.Header().Set("Content-Length", sizeParam)
+		if r.URL.Query().Get("chunked") != "true" {
+			w.Header().Set("Content-Length", sizeParam)
+		}
 
 		w.WriteHeader(http.StatusOK)
 		w.Write(data)
 
+	case "slow":
+		delay, _ := strconv.Atoi(r.URL.Query().Get("delay"))
+		time.Sleep(time.Duration(delay) * time.Millisecond)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{ "ok": "true" }`))
+	case "gone":
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{ "errors": ["unsubscribed"] }`))
 	case "unavailable":
 		w.WriteHeader(http.StatusServiceUnavailable)
 		w.Write([]byte(`{ "errors": ["service unavailable"] }`))
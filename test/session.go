@@ -3,10 +3,14 @@ package test
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/airtime"
 	"github.com/nyaruka/goflow/flows/assets"
 	"github.com/nyaruka/goflow/flows/engine"
 	"github.com/nyaruka/goflow/flows/events"
@@ -14,6 +18,9 @@ import (
 	"github.com/nyaruka/goflow/utils"
 )
 
+const registrationFlowUUID = flows.FlowUUID("50c3706e-fedb-42c0-8eab-dda3335714b7")
+const voiceFlowUUID = flows.FlowUUID("7a3ee4b8-8c45-4eb4-8c1f-2d6d4e6fc3f6")
+
 var sessionAssets = `[
     {
         "type": "channel_set",
@@ -42,6 +49,48 @@ var sessionAssets = `[
             }
         ]
     },
+    {
+        "type": "classifier_set",
+        "url": "http://testserver/assets/classifier",
+        "content": [
+            {
+                "uuid": "1c06c884-39dd-4ce4-ad9f-9a01cbe6c000",
+                "name": "Booking",
+                "intents": ["greeting", "book_flight", "book_hotel"],
+                "keywords": {
+                    "greeting": ["hi", "hello"],
+                    "book_flight": ["flight", "fly"],
+                    "book_hotel": ["hotel", "room"]
+                },
+                "entities": {
+                    "location": ["Paris", "Kigali"]
+                }
+            }
+        ]
+    },
+    {
+        "type": "resthook_set",
+        "url": "http://testserver/assets/resthook",
+        "content": [
+            {
+                "slug": "new-registration",
+                "subscribers": [
+                    "http://localhost:TEST_SERVER_PORT/?cmd=success",
+                    "http://localhost:TEST_SERVER_PORT/?cmd=gone"
+                ]
+            }
+        ]
+    },
+    {
+        "type": "secret_set",
+        "url": "http://testserver/assets/secret",
+        "content": [
+            {
+                "name": "Partner Key",
+                "value": "sesame"
+            }
+        ]
+    },
     {
         "type": "flow",
         "url": "http://testserver/assets/flow/50c3706e-fedb-42c0-8eab-dda3335714b7",
@@ -90,6 +139,21 @@ var sessionAssets = `[
                             "type": "call_webhook",
                             "method": "GET",
                             "url": "http://localhost:TEST_SERVER_PORT/?cmd=echo&content=%7B%22results%22%3A%5B%7B%22state%22%3A%22WA%22%7D%2C%7B%22state%22%3A%22IN%22%7D%5D%7D"
+                        },
+                        {
+                            "uuid": "3a5ed3bd-0c74-4a6b-9bb5-a1b3b4b8d8b3",
+                            "type": "call_classifier",
+                            "classifier": {
+                                "uuid": "1c06c884-39dd-4ce4-ad9f-9a01cbe6c000",
+                                "name": "Booking"
+                            },
+                            "input": "@run.input.text",
+                            "result_name": "Intent"
+                        },
+                        {
+                            "uuid": "b5ba5a31-8d0e-4b5a-9b5a-cf7d5c0d4e1f",
+                            "type": "transfer_airtime",
+                            "amounts": {"USD": 0.5, "RWF": 500}
                         }
                     ],
                     "exits": [
@@ -173,6 +237,16 @@ var sessionAssets = `[
             }
         ]
     },
+    {
+        "type": "ticketer_set",
+        "url": "http://testserver/assets/ticketer",
+        "content": [
+            {
+                "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+                "name": "Support Tickets"
+            }
+        ]
+    },
     {
         "type": "location_hierarchy",
         "url": "http://testserver/assets/location_hierarchy",
@@ -302,15 +376,31 @@ func CreateTestSession(testServerPort int, actionToAdd flows.Action) (flows.Sess
 	// different tests different ports for the test HTTP server
 	sessionAssets = strings.Replace(sessionAssets, "TEST_SERVER_PORT", fmt.Sprintf("%d", testServerPort), -1)
 
-	session, err := CreateSession(json.RawMessage(sessionAssets))
+	// include our voice flow fixture alongside the other assets
+	assetsJSON, err := includeFlowAsset(json.RawMessage(sessionAssets), "voice_flow.json")
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := CreateSession(assetsJSON)
 	if err != nil {
 		return nil, err
 	}
 
-	// optional modify the main flow by adding the provided action to the final empty node
+	// actions which can't be used in messaging flows are added to the voice flow instead of the main flow
+	flowUUID := registrationFlowUUID
+	if actionToAdd != nil && !allowsFlowType(actionToAdd, flows.FlowTypeMessaging) {
+		flowUUID = voiceFlowUUID
+	}
+	flow, err := session.Assets().GetFlow(flowUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// optional modify the flow by adding the provided action to the final empty node
 	if actionToAdd != nil {
-		flow, _ := session.Assets().GetFlow(flows.FlowUUID("50c3706e-fedb-42c0-8eab-dda3335714b7"))
-		flow.Nodes()[2].AddAction(actionToAdd)
+		nodes := flow.Nodes()
+		nodes[len(nodes)-1].AddAction(actionToAdd)
 	}
 
 	// read our trigger
@@ -323,6 +413,11 @@ func CreateTestSession(testServerPort int, actionToAdd flows.Action) (flows.Sess
 		return nil, fmt.Errorf("error reading trigger: %s", err)
 	}
 
+	// the voice flow is started manually for the same contact
+	if flowUUID == voiceFlowUUID {
+		trigger = triggers.NewManualTrigger(trigger.Environment(), trigger.Contact(), flow, trigger.Params(), trigger.TriggeredOn())
+	}
+
 	// and the initial events
 	eventEnvelopes := []*utils.TypedEnvelope{}
 	if err := json.Unmarshal(json.RawMessage(initialEvents), &eventEnvelopes); err != nil {
@@ -338,6 +433,48 @@ func CreateTestSession(testServerPort int, actionToAdd flows.Action) (flows.Sess
 	return session, err
 }
 
+// adds the flow definition in the given testdata file to the given list of assets
+func includeFlowAsset(assetsJSON json.RawMessage, fileName string) (json.RawMessage, error) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	flowJSON, err := ioutil.ReadFile(filepath.Join(filepath.Dir(thisFile), "testdata", fileName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading flow fixture: %s", err)
+	}
+
+	flow := &struct {
+		UUID flows.FlowUUID `json:"uuid"`
+	}{}
+	if err := json.Unmarshal(flowJSON, flow); err != nil {
+		return nil, fmt.Errorf("error unmarshalling flow fixture: %s", err)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(assetsJSON, &items); err != nil {
+		return nil, err
+	}
+
+	item, err := json.Marshal(map[string]interface{}{
+		"type":    "flow",
+		"url":     fmt.Sprintf("http://testserver/assets/flow/%s", flow.UUID),
+		"content": json.RawMessage(flowJSON),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(append(items, item))
+}
+
+// checks whether the given action can be used in flows of the given type
+func allowsFlowType(action flows.Action, flowType flows.FlowType) bool {
+	for _, t := range action.AllowedFlowTypes() {
+		if t == flowType {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateSession creates a session with the given assets
 func CreateSession(sessionAssets json.RawMessage) (flows.Session, error) {
 	// load our assets into a cache
@@ -349,6 +486,7 @@ func CreateSession(sessionAssets json.RawMessage) (flows.Session, error) {
 
 	// create our engine session
 	session := engine.NewSession(assetCache, assets.NewMockAssetServer(), engine.NewDefaultConfig(), TestHTTPClient)
+	session.SetAirtimeService(airtime.NewMockService("USD"))
 
 	// override the session environment
 	tz, _ := time.LoadLocation("America/Guayaquil")
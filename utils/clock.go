@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is something that can tell the current time, and wait for time to pass
+type Clock interface {
+	Now() time.Time
+	After(time.Duration) <-chan time.Time
+}
+
+// defaultClock returns the current system time
+type defaultClock struct{}
+
+// Now returns the current system time
+func (c defaultClock) Now() time.Time { return time.Now() }
+
+// After waits for the given duration to elapse and then sends the current system time on the returned channel
+func (c defaultClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// DefaultClock is the clock which returns the current system time
+var DefaultClock Clock = defaultClock{}
+
+// FixedClock is a clock which always returns the same time until it is changed. It's safe to share between sessions
+// running concurrently.
+type FixedClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+// NewFixedClock creates a new fixed clock set to the given time
+func NewFixedClock(now time.Time) *FixedClock {
+	return &FixedClock{now: now}
+}
+
+// Now returns the time this clock is set to
+func (c *FixedClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// SetNow sets the time this clock returns
+func (c *FixedClock) SetNow(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = now
+}
+
+// Advance moves this clock forward by the given duration
+func (c *FixedClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// After moves this clock forward by the given duration, and returns a channel on which the new time is already sent
+func (c *FixedClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
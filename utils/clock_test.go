@@ -0,0 +1,52 @@
+package utils_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedClock(t *testing.T) {
+	now := time.Date(2018, 7, 6, 12, 30, 0, 123456789, time.UTC)
+	clock := utils.NewFixedClock(now)
+
+	assert.Equal(t, now, clock.Now())
+	assert.Equal(t, now, clock.Now())
+
+	clock.Advance(time.Minute * 5)
+	assert.Equal(t, time.Date(2018, 7, 6, 12, 35, 0, 123456789, time.UTC), clock.Now())
+
+	clock.SetNow(now)
+	assert.Equal(t, now, clock.Now())
+
+	// waiting on a fixed clock doesn't take any time but moves the clock forward
+	assert.Equal(t, time.Date(2018, 7, 6, 12, 31, 0, 123456789, time.UTC), <-clock.After(time.Minute))
+	assert.Equal(t, time.Date(2018, 7, 6, 12, 31, 0, 123456789, time.UTC), clock.Now())
+	clock.SetNow(now)
+
+	// the default clock tells the real time
+	assert.WithinDuration(t, time.Now(), utils.DefaultClock.Now(), time.Second)
+}
+
+func TestFixedClockConcurrency(t *testing.T) {
+	now := time.Date(2018, 7, 6, 12, 30, 0, 0, time.UTC)
+	clock := utils.NewFixedClock(now)
+
+	// a clock shared between sessions can be waited on and read at the same time
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-clock.After(time.Second)
+			clock.Now()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, now.Add(time.Second*10), clock.Now())
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"encoding/json"
+	"math/rand"
 	"time"
 )
 
@@ -20,8 +21,10 @@ type Environment interface {
 	Timezone() *time.Location
 	Languages() LanguageList
 	RedactionPolicy() RedactionPolicy
+	Schedule() *Schedule
 
 	Now() time.Time
+	Rand() *rand.Rand
 }
 
 // NewDefaultEnvironment creates a new Environment with our usual defaults in the UTC timezone
@@ -46,12 +49,20 @@ func NewEnvironment(dateFormat DateFormat, timeFormat TimeFormat, timezone *time
 	}
 }
 
+// NewEnvironmentWithSchedule creates a new Environment like NewEnvironment but with a schedule of business hours
+func NewEnvironmentWithSchedule(dateFormat DateFormat, timeFormat TimeFormat, timezone *time.Location, languages LanguageList, redactionPolicy RedactionPolicy, schedule *Schedule) Environment {
+	env := NewEnvironment(dateFormat, timeFormat, timezone, languages, redactionPolicy).(*environment)
+	env.schedule = schedule
+	return env
+}
+
 type environment struct {
 	dateFormat      DateFormat
 	timeFormat      TimeFormat
 	timezone        *time.Location
 	languages       LanguageList
 	redactionPolicy RedactionPolicy
+	schedule        *Schedule
 }
 
 func (e *environment) DateFormat() DateFormat           { return e.dateFormat }
@@ -59,8 +70,10 @@ func (e *environment) TimeFormat() TimeFormat           { return e.timeFormat }
 func (e *environment) Timezone() *time.Location         { return e.timezone }
 func (e *environment) Languages() LanguageList          { return e.languages }
 func (e *environment) RedactionPolicy() RedactionPolicy { return e.redactionPolicy }
+func (e *environment) Schedule() *Schedule              { return e.schedule }
 
-func (e *environment) Now() time.Time { return time.Now().In(e.Timezone()) }
+func (e *environment) Now() time.Time   { return time.Now().In(e.Timezone()) }
+func (e *environment) Rand() *rand.Rand { return currentRand }
 
 //------------------------------------------------------------------------------------------
 // JSON Encoding / Decoding
@@ -72,6 +85,7 @@ type envEnvelope struct {
 	Timezone        string          `json:"timezone" validate:"required"`
 	Languages       LanguageList    `json:"languages"`
 	RedactionPolicy RedactionPolicy `json:"redaction_policy" validate:"omitempty,eq=none|eq=urns"`
+	Schedule        *Schedule       `json:"schedule,omitempty"`
 }
 
 // ReadEnvironment reads an environment from the given JSON
@@ -101,6 +115,8 @@ func ReadEnvironment(data json.RawMessage) (Environment, error) {
 		env.redactionPolicy = RedactionPolicyNone
 	}
 
+	env.schedule = envelope.Schedule
+
 	return env, nil
 }
 
@@ -112,6 +128,7 @@ func (e *environment) MarshalJSON() ([]byte, error) {
 		Timezone:        e.timezone.String(),
 		Languages:       e.languages,
 		RedactionPolicy: e.redactionPolicy,
+		Schedule:        e.schedule,
 	}
 	return json.Marshal(ee)
 }
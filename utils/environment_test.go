@@ -42,4 +42,17 @@ func TestEnvironmentMarshaling(t *testing.T) {
 	data, err := json.Marshal(env)
 	require.NoError(t, err)
 	assert.Equal(t, string(data), `{"date_format":"DD-MM-YYYY","time_format":"tt:mm:ss","timezone":"Africa/Kigali","languages":[],"redaction_policy":"none"}`)
+
+	// can create with a schedule of business hours
+	env, err = utils.ReadEnvironment(json.RawMessage(`{"date_format": "DD-MM-YYYY", "time_format": "tt:mm:ss", "timezone": "Africa/Kigali", "schedule": {"days": ["mon", "tue"], "start": "09:00", "end": "17:30", "holidays": ["2018-12-25"]}}`))
+	require.NoError(t, err)
+	require.NotNil(t, env.Schedule())
+
+	data, err = json.Marshal(env)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), `{"date_format":"DD-MM-YYYY","time_format":"tt:mm:ss","timezone":"Africa/Kigali","languages":[],"redaction_policy":"none","schedule":{"days":["mon","tue"],"start":"09:00","end":"17:30","holidays":["2018-12-25"]}}`)
+
+	// can't create with an invalid schedule
+	env, err = utils.ReadEnvironment(json.RawMessage(`{"date_format": "DD-MM-YYYY", "time_format": "tt:mm:ss", "timezone": "Africa/Kigali", "schedule": {"days": ["mon"], "start": "17:00", "end": "09:00"}}`))
+	assert.EqualError(t, err, "schedule end 09:00 must be after its start 17:00")
 }
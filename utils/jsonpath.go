@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+func init() {
+	Validator.RegisterValidation("json_path", func(fl validator.FieldLevel) bool {
+		_, err := ParseJSONPath(fl.Field().String())
+		return err == nil
+	})
+}
+
+// JSONPath is a parsed path to a value in a JSON document, e.g. `results[0].state`
+type JSONPath struct {
+	path string
+	keys []string
+}
+
+// ParseJSONPath parses the given path, which is a dot separated list of object keys, each of which may be followed
+// by one or more array indexes like `[2]`
+func ParseJSONPath(path string) (*JSONPath, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path can't be empty")
+	}
+
+	keys := make([]string, 0)
+
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			return nil, fmt.Errorf("path '%s' contains an empty key", path)
+		}
+
+		// split off the object key which precedes any array indexes
+		key := segment
+		indexes := ""
+		if bracket := strings.IndexRune(segment, '['); bracket >= 0 {
+			key, indexes = segment[:bracket], segment[bracket:]
+		}
+		if strings.ContainsAny(key, "[]") {
+			return nil, fmt.Errorf("path '%s' contains an invalid key '%s'", path, key)
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+
+		for indexes != "" {
+			end := strings.IndexRune(indexes, ']')
+			if indexes[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("path '%s' contains an invalid array index '%s'", path, indexes)
+			}
+			index, err := strconv.Atoi(indexes[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path '%s' contains an invalid array index '%s'", path, indexes[:end+1])
+			}
+
+			keys = append(keys, fmt.Sprintf("[%d]", index))
+			indexes = indexes[end+1:]
+		}
+	}
+
+	return &JSONPath{path: path, keys: keys}, nil
+}
+
+// Lookup looks up the value at this path in the given JSON document. Strings are returned unquoted, and objects and
+// arrays are returned as JSON. If there is no value at this path, or it is null, then found is false.
+func (p *JSONPath) Lookup(data []byte) (value string, found bool) {
+	val, valType, _, err := jsonparser.Get(data, p.keys...)
+	if err != nil || valType == jsonparser.Null || valType == jsonparser.NotExist {
+		return "", false
+	}
+
+	if valType == jsonparser.String {
+		str, err := jsonparser.ParseString(val)
+		if err != nil {
+			return "", false
+		}
+		return str, true
+	}
+
+	return string(val), true
+}
+
+// String returns the original form of this path
+func (p *JSONPath) String() string { return p.path }
@@ -0,0 +1,80 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	for _, path := range []string{"name", "results[0].state", "results[1][2]", "[0].name", "data.items[10].first name"} {
+		parsed, err := utils.ParseJSONPath(path)
+		assert.NoError(t, err, "unexpected error parsing '%s'", path)
+		assert.Equal(t, path, parsed.String())
+	}
+
+	errorTests := []struct {
+		path string
+		err  string
+	}{
+		{"", "path can't be empty"},
+		{"results..state", "path 'results..state' contains an empty key"},
+		{".state", "path '.state' contains an empty key"},
+		{"results.", "path 'results.' contains an empty key"},
+		{"results]", "path 'results]' contains an invalid key 'results]'"},
+		{"results[", "path 'results[' contains an invalid array index '['"},
+		{"results[x]", "path 'results[x]' contains an invalid array index '[x]'"},
+		{"results[-1]", "path 'results[-1]' contains an invalid array index '[-1]'"},
+		{"results[0]state", "path 'results[0]state' contains an invalid array index 'state'"},
+	}
+
+	for _, tc := range errorTests {
+		_, err := utils.ParseJSONPath(tc.path)
+		assert.EqualError(t, err, tc.err, "error mismatch parsing '%s'", tc.path)
+	}
+}
+
+func TestJSONPathLookup(t *testing.T) {
+	data := []byte(`{
+		"name": "Bob \"B\" Smith",
+		"age": 34,
+		"active": true,
+		"nickname": null,
+		"results": [{"state": "WA"}, {"state": "IN", "tags": ["x", "y"]}],
+		"address": {"city": "Seattle"}
+	}`)
+
+	tests := []struct {
+		path  string
+		value string
+		found bool
+	}{
+		{"name", `Bob "B" Smith`, true},
+		{"age", "34", true},
+		{"active", "true", true},
+		{"nickname", "", false},
+		{"results[0].state", "WA", true},
+		{"results[1].tags[1]", "y", true},
+		{"results[2].state", "", false},
+		{"results.state", "", false},
+		{"address", `{"city": "Seattle"}`, true},
+		{"address[0]", "", false},
+		{"missing.key", "", false},
+	}
+
+	for _, tc := range tests {
+		path, err := utils.ParseJSONPath(tc.path)
+		assert.NoError(t, err)
+
+		value, found := path.Lookup(data)
+		assert.Equal(t, tc.value, value, "value mismatch for '%s'", tc.path)
+		assert.Equal(t, tc.found, found, "found mismatch for '%s'", tc.path)
+	}
+
+	// lookups in documents which aren't JSON never find anything
+	path, _ := utils.ParseJSONPath("name")
+	_, found := path.Lookup([]byte(`<name>Bob</name>`))
+	assert.False(t, found)
+}
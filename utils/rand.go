@@ -7,7 +7,7 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// DefaultRand is the default rand for calls to Rand()
+// DefaultRand is the default rand for environments which aren't part of a session
 var DefaultRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 var currentRand = DefaultRand
 
@@ -16,17 +16,12 @@ func NewSeededRand(seed int64) *rand.Rand {
 	return rand.New(rand.NewSource(seed))
 }
 
-// RandDecimal returns a random decimal in the range [0.0, 1.0)
-func RandDecimal() decimal.Decimal {
-	return decimal.NewFromFloat(currentRand.Float64())
+// RandDecimal returns a random decimal in the range [0.0, 1.0) from the given rand
+func RandDecimal(rnd *rand.Rand) decimal.Decimal {
+	return decimal.NewFromFloat(rnd.Float64())
 }
 
-// RandIntN returns a random integer in the range [0, n)
-func RandIntN(n int) int {
-	return currentRand.Intn(n)
-}
-
-// SetRand sets the rand used by Rand()
+// SetRand sets the rand used by environments which aren't part of a session
 func SetRand(rnd *rand.Rand) {
 	currentRand = rnd
 }
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule describes business hours, i.e. the days of the week and the hours of those days which are worked, and
+// the dates of holidays which aren't worked.
+type Schedule struct {
+	days     map[time.Weekday]bool
+	start    time.Duration
+	end      time.Duration
+	holidays map[string]bool
+}
+
+// NewSchedule creates a new schedule which works between start and end (given as offsets from midnight) on the given
+// days, except on the given holidays
+func NewSchedule(days []time.Weekday, start time.Duration, end time.Duration, holidays []time.Time) *Schedule {
+	s := &Schedule{
+		days:     make(map[time.Weekday]bool, len(days)),
+		start:    start,
+		end:      end,
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, day := range days {
+		s.days[day] = true
+	}
+	for _, holiday := range holidays {
+		s.holidays[holiday.Format("2006-01-02")] = true
+	}
+	return s
+}
+
+// IsWorkingDay returns whether the date of the given time is worked
+func (s *Schedule) IsWorkingDay(t time.Time) bool {
+	return s.days[t.Weekday()] && !s.holidays[t.Format("2006-01-02")]
+}
+
+// AddWorkingDuration returns the time when the given duration of working time will have elapsed after the given
+// start time, e.g. 2 hours after 4pm on a Friday is 10am on the Monday if the schedule works 9am to 5pm on weekdays.
+// Days are determined by the location of the given start time.
+func (s *Schedule) AddWorkingDuration(start time.Time, duration time.Duration) time.Time {
+	// a schedule which never works would never let any time elapse
+	if len(s.days) == 0 || s.end <= s.start {
+		return start.Add(duration)
+	}
+
+	t := start
+	remaining := duration
+
+	for {
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+		if s.IsWorkingDay(t) {
+			dayStart := midnight.Add(s.start)
+			dayEnd := midnight.Add(s.end)
+
+			if t.Before(dayStart) {
+				t = dayStart
+			}
+			if t.Before(dayEnd) {
+				available := dayEnd.Sub(t)
+				if remaining <= available {
+					return t.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type scheduleEnvelope struct {
+	Days     []string `json:"days" validate:"min=1,dive,eq=sun|eq=mon|eq=tue|eq=wed|eq=thu|eq=fri|eq=sat"`
+	Start    string   `json:"start" validate:"required"`
+	End      string   `json:"end" validate:"required"`
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+// parses a time of day like 09:30 into an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day '%s', expected HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// formats an offset from midnight as a time of day like 09:30
+func formatTimeOfDay(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+// ReadSchedule reads a schedule from the given JSON
+func ReadSchedule(data json.RawMessage) (*Schedule, error) {
+	var envelope scheduleEnvelope
+	if err := UnmarshalAndValidate(data, &envelope, "schedule"); err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Weekday, len(envelope.Days))
+	for d, day := range envelope.Days {
+		days[d] = scheduleDays[day]
+	}
+
+	start, err := parseTimeOfDay(envelope.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTimeOfDay(envelope.End)
+	if err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, fmt.Errorf("schedule end %s must be after its start %s", envelope.End, envelope.Start)
+	}
+
+	holidays := make([]time.Time, len(envelope.Holidays))
+	for h, holiday := range envelope.Holidays {
+		if holidays[h], err = time.Parse("2006-01-02", holiday); err != nil {
+			return nil, fmt.Errorf("invalid holiday '%s', expected YYYY-MM-DD", holiday)
+		}
+	}
+
+	return NewSchedule(days, start, end, holidays), nil
+}
+
+// UnmarshalJSON unmarshals this schedule from JSON
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	schedule, err := ReadSchedule(data)
+	if err != nil {
+		return err
+	}
+	*s = *schedule
+	return nil
+}
+
+// MarshalJSON marshals this schedule into JSON
+func (s *Schedule) MarshalJSON() ([]byte, error) {
+	envelope := scheduleEnvelope{Start: formatTimeOfDay(s.start), End: formatTimeOfDay(s.end)}
+
+	for _, day := range []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"} {
+		if s.days[scheduleDays[day]] {
+			envelope.Days = append(envelope.Days, day)
+		}
+	}
+	for holiday := range s.holidays {
+		envelope.Holidays = append(envelope.Holidays, holiday)
+	}
+	sort.Strings(envelope.Holidays)
+
+	return json.Marshal(envelope)
+}
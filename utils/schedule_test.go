@@ -0,0 +1,83 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleAddWorkingDuration(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	christmas := time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC)
+	schedule := utils.NewSchedule(weekdays, 9*time.Hour, 17*time.Hour, []time.Time{christmas})
+
+	tests := []struct {
+		start    time.Time
+		duration time.Duration
+		expected time.Time
+	}{
+		// within a single working day
+		{time.Date(2018, 12, 4, 10, 0, 0, 0, la), 2 * time.Hour, time.Date(2018, 12, 4, 12, 0, 0, 0, la)},
+
+		// ending exactly at the end of the working day
+		{time.Date(2018, 12, 4, 15, 0, 0, 0, la), 2 * time.Hour, time.Date(2018, 12, 4, 17, 0, 0, 0, la)},
+
+		// started late in the evening so the next morning
+		{time.Date(2018, 12, 4, 23, 0, 0, 0, la), 2 * time.Hour, time.Date(2018, 12, 5, 11, 0, 0, 0, la)},
+
+		// started before the working day
+		{time.Date(2018, 12, 4, 6, 0, 0, 0, la), 30 * time.Minute, time.Date(2018, 12, 4, 9, 30, 0, 0, la)},
+
+		// carried over the weekend
+		{time.Date(2018, 12, 7, 16, 0, 0, 0, la), 2 * time.Hour, time.Date(2018, 12, 10, 10, 0, 0, 0, la)},
+
+		// carried over a holiday
+		{time.Date(2018, 12, 24, 16, 0, 0, 0, la), 2 * time.Hour, time.Date(2018, 12, 26, 10, 0, 0, 0, la)},
+
+		// spanning several working days
+		{time.Date(2018, 12, 3, 9, 0, 0, 0, la), 20 * time.Hour, time.Date(2018, 12, 5, 13, 0, 0, 0, la)},
+	}
+
+	for _, tc := range tests {
+		actual := schedule.AddWorkingDuration(tc.start, tc.duration)
+		assert.Equal(t, tc.expected, actual, "add working duration mismatch for %s + %s", tc.start, tc.duration)
+	}
+
+	// a schedule without working days falls back to elapsed time
+	never := utils.NewSchedule(nil, 9*time.Hour, 17*time.Hour, nil)
+	start := time.Date(2018, 12, 4, 23, 0, 0, 0, la)
+	assert.Equal(t, start.Add(2*time.Hour), never.AddWorkingDuration(start, 2*time.Hour))
+}
+
+func TestScheduleMarshaling(t *testing.T) {
+	schedule, err := utils.ReadSchedule(json.RawMessage(`{"days": ["fri", "mon"], "start": "08:30", "end": "16:00", "holidays": ["2019-01-01", "2018-12-25"]}`))
+	require.NoError(t, err)
+
+	assert.True(t, schedule.IsWorkingDay(time.Date(2018, 12, 7, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsWorkingDay(time.Date(2018, 12, 8, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsWorkingDay(time.Date(2018, 12, 31, 12, 0, 0, 0, time.UTC).AddDate(0, 0, 1)))
+
+	data, err := json.Marshal(schedule)
+	require.NoError(t, err)
+	assert.Equal(t, `{"days":["mon","fri"],"start":"08:30","end":"16:00","holidays":["2018-12-25","2019-01-01"]}`, string(data))
+
+	_, err = utils.ReadSchedule(json.RawMessage(`{"days": [], "start": "08:30", "end": "16:00"}`))
+	assert.Error(t, err)
+
+	_, err = utils.ReadSchedule(json.RawMessage(`{"days": ["xyz"], "start": "08:30", "end": "16:00"}`))
+	assert.Error(t, err)
+
+	_, err = utils.ReadSchedule(json.RawMessage(`{"days": ["mon"], "start": "8am", "end": "16:00"}`))
+	assert.EqualError(t, err, "invalid time of day '8am', expected HH:MM")
+
+	_, err = utils.ReadSchedule(json.RawMessage(`{"days": ["mon"], "start": "08:30", "end": "16:00", "holidays": ["25/12/2018"]}`))
+	assert.EqualError(t, err, "invalid holiday '25/12/2018', expected YYYY-MM-DD")
+}
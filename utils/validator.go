@@ -94,6 +94,8 @@ func validate(obj interface{}, objName string) error {
 			problem = fmt.Sprintf("is mutually exclusive with '%s'", fieldErr.Param())
 		case "http_method":
 			problem = "is not a valid HTTP method"
+		case "json_path":
+			problem = "is not a valid JSON path"
 		default:
 			problem = fmt.Sprintf("failed tag '%s'", fieldErr.Tag())
 		}
@@ -19,6 +19,7 @@ type TestObject struct {
 	Foo    string    `json:"foo" validate:"required"`
 	Bar    SubObject `json:"bar" validate:"required"`
 	Things []string  `json:"things" validate:"min=1,max=3,dive,http_method"`
+	Path   string    `json:"path" validate:"omitempty,json_path"`
 }
 
 func TestValidate(t *testing.T) {
@@ -65,6 +66,7 @@ func TestValidate(t *testing.T) {
 			SomeValue: 2,
 		},
 		Things: []string{"UGHHH"},
+		Path:   "results[x]",
 	}, "blob.thing.test_object")
 	assert.NotNil(t, errs)
 
@@ -72,5 +74,6 @@ func TestValidate(t *testing.T) {
 	msgs = strings.Split(errs.Error(), ", ")
 	assert.Equal(t, []string{
 		`field 'blob.thing.test_object.things[0]' is not a valid HTTP method`,
+		`field 'blob.thing.test_object.path' is not a valid JSON path`,
 	}, msgs)
 }